@@ -166,3 +166,30 @@ func NewServiceUnavailable(internal error) *AppError {
 		Internal: internal,
 	}
 }
+
+// NewMaintenance builds an AppError for HTTP 503 when a service is
+// deliberately refusing writes for planned maintenance, as opposed to
+// NewServiceUnavailable's unplanned downstream failure. Unlike every other
+// constructor here, its public message says what is going on - an
+// operator-announced maintenance window is not internal detail, and a
+// client seeing it knows retrying later, not immediately, is the right
+// move.
+func NewMaintenance(internal error) *AppError {
+	return &AppError{
+		Status:   http.StatusServiceUnavailable,
+		Public:   "temporarily unavailable for maintenance",
+		Internal: internal,
+	}
+}
+
+// NewMethodNotAllowed builds an AppError for HTTP 405: a request to a known
+// endpoint using a method that endpoint does not serve. RFC 9110 §15.5.6
+// requires the response to carry an Allow header, which this type cannot
+// set; pkg/httpmethod.Allow writes both.
+func NewMethodNotAllowed(internal error) *AppError {
+	return &AppError{
+		Status:   http.StatusMethodNotAllowed,
+		Public:   "method not allowed",
+		Internal: internal,
+	}
+}
@@ -27,6 +27,8 @@ func TestAppError_ConstructorsSetStatusAndSafePublicMessage(t *testing.T) {
 		{"bad gateway", NewBadGateway, http.StatusBadGateway},
 		{"gateway timeout", NewGatewayTimeout, http.StatusGatewayTimeout},
 		{"service unavailable", NewServiceUnavailable, http.StatusServiceUnavailable},
+		{"maintenance", NewMaintenance, http.StatusServiceUnavailable},
+		{"method not allowed", NewMethodNotAllowed, http.StatusMethodNotAllowed},
 	}
 
 	for _, tc := range cases {
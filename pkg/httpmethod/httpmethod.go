@@ -0,0 +1,55 @@
+// Package httpmethod enforces which HTTP methods an endpoint serves, in
+// one place for every RAM-USB service. Every JSON endpoint is POST-only
+// (EH-F-01..EH-F-03 and their internal counterparts) and the public-key
+// lookup is GET-only (ST-F-11); before this package, only Entry-Hub
+// rejected other methods at all, via ServeMux method patterns, and
+// nothing logged the attempt.
+//
+// A rejected request gets HTTP 405 with the Allow header RFC 9110 §15.5.6
+// requires, the same {"error": "..."} body shape every service's
+// writeAppError produces, and a warning in the log naming the method used.
+package httpmethod
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	apperrors "github.com/Verryx-02/RAM-USB/pkg/errors"
+	"github.com/Verryx-02/RAM-USB/pkg/logging"
+)
+
+// errorResponse mirrors each service's appErrorResponse, so a 405 body is
+// indistinguishable in shape from any other error the service writes.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Allow returns a handler that calls next only for a request whose method
+// is one of allowed, and otherwise responds HTTP 405 with an Allow header
+// listing allowed in the order given. A GET endpoint should list
+// http.MethodHead too if it wants to serve HEAD; Allow does not add it
+// implicitly the way a ServeMux "GET " pattern does.
+func Allow(next http.HandlerFunc, allowed ...string) http.HandlerFunc {
+	allowHeader := strings.Join(allowed, ", ")
+	return func(w http.ResponseWriter, r *http.Request) {
+		if slices.Contains(allowed, r.Method) {
+			next(w, r)
+			return
+		}
+
+		appErr := apperrors.NewMethodNotAllowed(fmt.Errorf("httpmethod: %s not allowed, want %s", r.Method, allowHeader))
+		slog.Warn("method not allowed",
+			"method", logging.Sanitize(r.Method),
+			"path", logging.Sanitize(r.URL.Path),
+			"allow", allowHeader)
+
+		w.Header().Set("Allow", allowHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(appErr.Status)
+		_ = json.NewEncoder(w).Encode(errorResponse{Error: appErr.Public})
+	}
+}
@@ -0,0 +1,61 @@
+package httpmethod
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Requirement: EH-F-01
+func TestAllow(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		allowed    []string
+		wantCalled bool
+		wantStatus int
+		wantAllow  string
+	}{
+		{name: "POST to a POST-only endpoint", method: http.MethodPost, allowed: []string{http.MethodPost}, wantCalled: true, wantStatus: http.StatusNoContent},
+		{name: "GET to a POST-only endpoint", method: http.MethodGet, allowed: []string{http.MethodPost}, wantStatus: http.StatusMethodNotAllowed, wantAllow: "POST"},
+		{name: "PUT to a POST-only endpoint", method: http.MethodPut, allowed: []string{http.MethodPost}, wantStatus: http.StatusMethodNotAllowed, wantAllow: "POST"},
+		{name: "HEAD to a GET and HEAD endpoint", method: http.MethodHead, allowed: []string{http.MethodGet, http.MethodHead}, wantCalled: true, wantStatus: http.StatusNoContent},
+		{name: "DELETE to a GET and HEAD endpoint", method: http.MethodDelete, allowed: []string{http.MethodGet, http.MethodHead}, wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := Allow(func(w http.ResponseWriter, _ *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusNoContent)
+			}, tt.allowed...)
+
+			req := httptest.NewRequestWithContext(context.Background(), tt.method, "/api/register", nil)
+			rec := httptest.NewRecorder()
+			h(rec, req)
+
+			if called != tt.wantCalled {
+				t.Fatalf("next called = %v, want %v", called, tt.wantCalled)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Allow"); got != tt.wantAllow {
+				t.Fatalf("Allow = %q, want %q", got, tt.wantAllow)
+			}
+			if tt.wantCalled {
+				return
+			}
+			var body errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Error != "method not allowed" {
+				t.Fatalf("body error = %q, want %q", body.Error, "method not allowed")
+			}
+		})
+	}
+}
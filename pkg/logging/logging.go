@@ -13,6 +13,10 @@
 // These are deliberately separate mechanisms: Redacted hides a value
 // entirely, Sanitize keeps a value's content but neutralizes the specific
 // characters that make it dangerous to write to a log stream.
+//
+// NewRedactingHandler backs Redacted up at the handler: every service's
+// default logger masks the value of any SensitiveKeys attribute, so a
+// credential logged without Redacted by mistake is still hidden.
 package logging
 
 import (
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// SensitiveKeys are the attribute keys every service's logger masks
+// through NewRedactingHandler: the login credentials (DV-F-03, RD-01)
+// and the SSH public key that identifies a user to Storage-Service.
+var SensitiveKeys = []string{"email", "password", "ssh_public_key"}
+
+// redactedValue is what NewRedactingHandler logs in place of a masked
+// value, the same marker Redacted prints.
+var redactedValue = slog.StringValue("REDACTED")
+
+// NewRedactingHandler returns a slog.Handler that passes every record to
+// inner with the value of any attribute whose key matches one of keys
+// (case-insensitively) replaced by "REDACTED". Keys are matched at any
+// depth: inside slog.Group attributes, inside attributes added with
+// Logger.With, and inside map values logged with slog.Any.
+//
+// It is a safety net behind Redacted, not a replacement for it: a
+// credential logged under some other key, or formatted into a message or
+// an error string, still gets through. Wrapping a Redacted value is
+// harmless, it prints "REDACTED" either way.
+func NewRedactingHandler(inner slog.Handler, keys []string) slog.Handler {
+	sensitive := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		sensitive[strings.ToLower(key)] = true
+	}
+	return &redactingHandler{inner: inner, sensitive: sensitive}
+}
+
+// NewLogger returns the logger every service installs with slog.SetDefault
+// at startup: a slog.TextHandler writing to w, behind NewRedactingHandler
+// with SensitiveKeys. It builds its own TextHandler rather than wrapping
+// slog.Default's handler, which writes through package log and, once
+// installed as the default, would loop back into itself.
+func NewLogger(w io.Writer) *slog.Logger {
+	return slog.New(NewRedactingHandler(slog.NewTextHandler(w, nil), SensitiveKeys))
+}
+
+// redactingHandler is NewRedactingHandler's slog.Handler.
+type redactingHandler struct {
+	inner     slog.Handler
+	sensitive map[string]bool
+}
+
+// Enabled reports whether inner handles records at level.
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle passes a copy of r with its attributes redacted to inner.
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, redacted)
+}
+
+// WithAttrs redacts attrs before inner stores them.
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	return &redactingHandler{inner: h.inner.WithAttrs(redacted), sensitive: h.sensitive}
+}
+
+// WithGroup returns a redactingHandler over inner's group handler.
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{inner: h.inner.WithGroup(name), sensitive: h.sensitive}
+}
+
+// redactAttr returns a with its value masked if its key is sensitive, or
+// with any sensitive keys nested in its group or map value masked.
+func (h *redactingHandler) redactAttr(a slog.Attr) slog.Attr {
+	if h.sensitive[strings.ToLower(a.Key)] {
+		return slog.Attr{Key: a.Key, Value: redactedValue}
+	}
+
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = h.redactAttr(member)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindAny:
+		if m, ok := h.redactMap(value.Any()); ok {
+			return slog.Any(a.Key, m)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
+
+// redactMap returns a copy of v with sensitive keys masked at any depth,
+// if v is a map with string keys. A map is copied rather than edited so
+// the caller's value is left untouched.
+func (h *redactingHandler) redactMap(v any) (map[string]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	redacted := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		value := iter.Value().Interface()
+		if h.sensitive[strings.ToLower(key)] {
+			redacted[key] = redactedValue.String()
+		} else if nested, ok := h.redactMap(value); ok {
+			redacted[key] = nested
+		} else {
+			redacted[key] = value
+		}
+	}
+	return redacted, true
+}
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// newRedactingTestLogger returns a logger writing JSON through
+// NewRedactingHandler with SensitiveKeys into the returned buffer.
+func newRedactingTestLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(NewRedactingHandler(slog.NewJSONHandler(&buf, nil), SensitiveKeys)), &buf
+}
+
+// Requirement: RD-01
+func TestRedactingHandler_MasksSensitiveKeys(t *testing.T) {
+	const plaintextEmail = "victim@example.com"
+
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+	}{
+		{
+			name: "top-level attribute",
+			log:  func(l *slog.Logger) { l.Info("registered", "email", plaintextEmail) },
+		},
+		{
+			name: "key matched case-insensitively",
+			log:  func(l *slog.Logger) { l.Info("registered", "Email", plaintextEmail) },
+		},
+		{
+			name: "map value",
+			log: func(l *slog.Logger) {
+				l.Info("request", "body", map[string]any{"email": plaintextEmail, "attempt": 2})
+			},
+		},
+		{
+			name: "nested map value",
+			log: func(l *slog.Logger) {
+				l.Info("request", "body", map[string]any{"user": map[string]string{"email": plaintextEmail}})
+			},
+		},
+		{
+			name: "group attribute",
+			log:  func(l *slog.Logger) { l.Info("request", slog.Group("user", "email", plaintextEmail)) },
+		},
+		{
+			name: "attribute added with With",
+			log:  func(l *slog.Logger) { l.With("email", plaintextEmail).Info("registered") },
+		},
+		{
+			name: "attribute under WithGroup",
+			log:  func(l *slog.Logger) { l.WithGroup("user").Info("registered", "email", plaintextEmail) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newRedactingTestLogger()
+			tt.log(logger)
+
+			output := buf.String()
+			if strings.Contains(output, plaintextEmail) {
+				t.Errorf("log output contains plaintext email: %q", output)
+			}
+			if !strings.Contains(output, "REDACTED") {
+				t.Errorf("log output missing REDACTED marker: %q", output)
+			}
+		})
+	}
+}
+
+// Requirement: RD-01
+func TestRedactingHandler_KeepsOtherValues(t *testing.T) {
+	logger, buf := newRedactingTestLogger()
+	body := map[string]any{"email": "victim@example.com", "status": "created"}
+
+	logger.Info("request", "body", body, "service", "Entry-Hub")
+
+	output := buf.String()
+	for _, want := range []string{`"status":"created"`, `"service":"Entry-Hub"`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("log output missing %s: %q", want, output)
+		}
+	}
+	if body["email"] != "victim@example.com" {
+		t.Errorf("caller's map was modified: email = %v", body["email"])
+	}
+}
+
+// Requirement: RD-01
+func TestNewLogger_RedactsSensitiveKeys(t *testing.T) {
+	var buf bytes.Buffer
+	NewLogger(&buf).Info("registered", "email", "victim@example.com", "service", "Entry-Hub")
+
+	output := buf.String()
+	if strings.Contains(output, "victim@example.com") || !strings.Contains(output, "email=REDACTED") {
+		t.Errorf("log output = %q, want email=REDACTED", output)
+	}
+	if !strings.Contains(output, "service=Entry-Hub") {
+		t.Errorf("log output = %q, want service=Entry-Hub kept", output)
+	}
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// FailureThresholdEnvVar names the environment variable every publishing
+// service reads its publish-failure alert threshold from (see
+// LoadFailureAlert), as a fraction in (0, 1]. Shared across services,
+// same as RAM_USB_MQTT_BROKER_URL, since the threshold means the same
+// thing for each of them.
+const FailureThresholdEnvVar = "RAM_USB_METRICS_PUBLISH_FAILURE_THRESHOLD"
+
+// DefaultFailureThreshold is FailureThresholdEnvVar's fallback: alert once
+// at least half of a window's publish cycles failed. A single failed
+// publish (a broker restart, one dropped acknowledgement) stays below it.
+const DefaultFailureThreshold = 0.5
+
+// FailureWindow is how many publish cycles FailureAlert evaluates the
+// failure rate over before resetting: ten minutes at every service's
+// once-per-minute publish interval.
+const FailureWindow = 10
+
+// FailureAlertKey is the "alert" attribute value FailureAlert's log line
+// carries, a fixed string for log-based monitoring to match on rather than
+// the message text.
+const FailureAlertKey = "metrics_publish_failing"
+
+// ErrInvalidFailureThreshold is returned by NewFailureAlert and
+// LoadFailureAlert for a threshold outside (0, 1].
+var ErrInvalidFailureThreshold = errors.New("metrics: failure threshold must be greater than 0 and at most 1")
+
+// FailureAlert turns Run's individual "publish cycle failed" log lines
+// into an aggregate signal an operator can alert on: it counts publish
+// outcomes over a window of cycles and, if the window's failure rate
+// reaches its threshold, logs one distinct error line (tagged
+// "alert"=FailureAlertKey) at the end of that window. Counts then reset,
+// so a recovered publisher stops alerting after one clean window and a
+// still-broken one alerts again once per window rather than once per
+// cycle.
+type FailureAlert struct {
+	window    int
+	threshold float64
+
+	mu       sync.Mutex
+	attempts int
+	failures int
+}
+
+// NewFailureAlert returns a FailureAlert evaluating every window publish
+// cycles against threshold.
+func NewFailureAlert(window int, threshold float64) (*FailureAlert, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("metrics: failure window must be at least 1, got %d", window)
+	}
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("%w, got %v", ErrInvalidFailureThreshold, threshold)
+	}
+	return &FailureAlert{window: window, threshold: threshold}, nil
+}
+
+// LoadFailureAlert returns a FailureAlert over FailureWindow cycles, with
+// its threshold read from FailureThresholdEnvVar (DefaultFailureThreshold
+// if unset or empty). A value present but unparseable or out of range is
+// an error rather than silently replaced by the default (RD-04).
+func LoadFailureAlert() (*FailureAlert, error) {
+	threshold := DefaultFailureThreshold
+	if value, ok := os.LookupEnv(FailureThresholdEnvVar); ok && value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("metrics: environment variable %s is not a valid number: %w", FailureThresholdEnvVar, err)
+		}
+		threshold = parsed
+	}
+	return NewFailureAlert(FailureWindow, threshold)
+}
+
+// Wrap returns a PublishFunc that calls publish and records its outcome,
+// for passing to Run in place of publish itself. publish's error is
+// returned unchanged, so Run still logs each individual failure.
+func (a *FailureAlert) Wrap(publish PublishFunc) PublishFunc {
+	return func(ctx context.Context) error {
+		err := publish(ctx)
+		a.Observe(err)
+		return err
+	}
+}
+
+// Observe records one publish cycle's outcome (err == nil for success). It
+// reports whether this cycle closed a window whose failure rate reached
+// the threshold, i.e. whether it just logged the alert.
+func (a *FailureAlert) Observe(err error) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.attempts++
+	if err != nil {
+		a.failures++
+	}
+	if a.attempts < a.window {
+		return false
+	}
+
+	attempts, failures := a.attempts, a.failures
+	a.attempts, a.failures = 0, 0
+
+	rate := float64(failures) / float64(attempts)
+	if rate < a.threshold {
+		return false
+	}
+
+	slog.Error("metrics: publish failure rate above threshold",
+		"alert", FailureAlertKey,
+		"failures", failures,
+		"attempts", attempts,
+		"failure_rate", rate,
+		"threshold", a.threshold)
+	return true
+}
@@ -0,0 +1,127 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Verryx-02/RAM-USB/pkg/metrics"
+)
+
+// Requirement: EH-F-10
+func TestFailureAlert_AlertsOnlyAtOrAboveThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		wantAlert bool
+	}{
+		{name: "no failures", failures: 0, wantAlert: false},
+		{name: "one transient failure", failures: 1, wantAlert: false},
+		{name: "just below threshold", failures: 4, wantAlert: false},
+		{name: "exactly at threshold", failures: 5, wantAlert: true},
+		{name: "every cycle failing", failures: 10, wantAlert: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert, err := metrics.NewFailureAlert(10, 0.5)
+			if err != nil {
+				t.Fatalf("NewFailureAlert() error = %v", err)
+			}
+
+			var alerted bool
+			for i := 0; i < 10; i++ {
+				var cycleErr error
+				if i < tt.failures {
+					cycleErr = errPublishFailedForTest
+				}
+				fired := alert.Observe(cycleErr)
+				if i < 9 && fired {
+					t.Fatalf("Observe() alerted at cycle %d, before the window closed", i+1)
+				}
+				alerted = fired
+			}
+
+			if alerted != tt.wantAlert {
+				t.Fatalf("alerted = %v after %d/10 failures, want %v", alerted, tt.failures, tt.wantAlert)
+			}
+		})
+	}
+}
+
+// Requirement: EH-F-10
+func TestFailureAlert_WindowResets(t *testing.T) {
+	alert, err := metrics.NewFailureAlert(4, 0.5)
+	if err != nil {
+		t.Fatalf("NewFailureAlert() error = %v", err)
+	}
+
+	// A fully failing window alerts...
+	var alerted bool
+	for i := 0; i < 4; i++ {
+		alerted = alert.Observe(errPublishFailedForTest)
+	}
+	if !alerted {
+		t.Fatal("a fully failing window did not alert")
+	}
+
+	// ...and its failures do not carry into the next, clean, window.
+	for i := 0; i < 4; i++ {
+		alerted = alert.Observe(nil)
+	}
+	if alerted {
+		t.Fatal("a clean window after a failing one alerted, want the counts reset")
+	}
+}
+
+// Requirement: EH-F-10
+func TestFailureAlert_WrapRecordsAndReturnsPublishError(t *testing.T) {
+	alert, err := metrics.NewFailureAlert(1, 1)
+	if err != nil {
+		t.Fatalf("NewFailureAlert() error = %v", err)
+	}
+
+	publish := alert.Wrap(func(context.Context) error { return errPublishFailedForTest })
+	if err := publish(context.Background()); !errors.Is(err, errPublishFailedForTest) {
+		t.Fatalf("wrapped publish error = %v, want the original error returned unchanged", err)
+	}
+
+	// The wrapped failure closed a one-cycle window at 100%; one more
+	// failure must alert again, confirming Wrap fed Observe.
+	if !alert.Observe(errPublishFailedForTest) {
+		t.Fatal("Observe() did not alert on a fully failing one-cycle window")
+	}
+}
+
+// Requirement: EH-F-10
+func TestLoadFailureAlert(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "unset uses default", value: ""},
+		{name: "valid fraction", value: "0.25"},
+		{name: "one", value: "1"},
+		{name: "zero", value: "0", wantErr: true},
+		{name: "above one", value: "1.5", wantErr: true},
+		{name: "not a number", value: "half", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(metrics.FailureThresholdEnvVar, tt.value)
+
+			alert, err := metrics.LoadFailureAlert()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadFailureAlert() error = nil, want non-nil")
+				}
+				return
+			}
+			if err != nil || alert == nil {
+				t.Fatalf("LoadFailureAlert() = (%v, %v), want a FailureAlert", alert, err)
+			}
+		})
+	}
+}
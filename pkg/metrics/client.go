@@ -1,8 +1,9 @@
 // Package metrics implements the periodic MQTT metrics publish every
 // RAM-USB service performs: building an aggregated-only payload
 // (BuildPayload/Payload/Counters), an mTLS-verified MQTT client to send it
-// (NewClient/TLSConfig), and a once-per-minute scheduling loop
-// (Run/PublishOnce). It backs EH-F-10/EH-F-11, SS-F-07/SS-F-08,
+// (NewClient/TLSConfig), a once-per-minute scheduling loop
+// (Run/PublishOnce), and an aggregate publish-failure alert over that loop
+// (FailureAlert). It backs EH-F-10/EH-F-11, SS-F-07/SS-F-08,
 // DV-F-16/DV-F-17, ST-F-12/ST-F-13, NM-F-17/NM-F-18, and CA-F-03: every
 // one of those requirements is identical modulo which service is
 // publishing, so the client construction, TLS verification, payload
@@ -20,25 +21,73 @@ package metrics
 import (
 	"crypto/tls"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
 
+// MaxReconnectInterval caps paho's exponential backoff between failed
+// reconnect attempts (paho's own default is ten minutes - nine missed
+// publishes at once per minute). It also bounds ReconnectJitter.
+const MaxReconnectInterval = time.Minute
+
+// KeepAlive, PingTimeout, and WriteTimeout bound how long a connected
+// client waits on a broker that has stopped responding. KeepAlive and
+// PingTimeout match paho's current defaults but are set explicitly so a
+// paho upgrade cannot change them; WriteTimeout replaces paho's default of
+// no timeout at all, under which a broker that stops reading would block
+// a publish indefinitely.
+const (
+	KeepAlive    = 30 * time.Second
+	PingTimeout  = 10 * time.Second
+	WriteTimeout = 10 * time.Second
+)
+
+// maxReconnectJitter caps ReconnectJitter regardless of
+// MaxReconnectInterval, so a long backoff cap does not also mean a long
+// first reconnect after a one-off blip.
+const maxReconnectJitter = 10 * time.Second
+
+// ReconnectJitter returns a random delay in [0, min(maxInterval,
+// maxReconnectJitter)) that NewClient's client sleeps before every
+// reconnect attempt. After a broker restart every service loses its
+// connection at the same instant, and paho's first reconnect attempt is
+// immediate; without this, all of them would hit the broker together
+// (and, backing off identically, keep doing so on every retry).
+func ReconnectJitter(maxInterval time.Duration) time.Duration {
+	bound := min(maxInterval, maxReconnectJitter)
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound) //nolint:gosec // spreading reconnects needs no cryptographic randomness
+}
+
 // NewClient builds and connects a paho MQTT client for publishing/
 // subscribing to brokerURL (e.g. "tls://mqtt-broker.internal:8883") over
 // mTLS, presenting and verifying certificates per tlsConfig (see TLSConfig -
 // the caller builds tlsConfig from its own already-bootstrapped mTLS
 // identity, reused for this MQTT connection rather than a second,
 // independent certificate). It blocks until the connection completes or
-// connectTimeout elapses.
+// connectTimeout elapses - connectTimeout bounds the TCP dial and TLS
+// handshake too, so a broker that accepts the connection and then stalls
+// cannot hang startup. Once connected, KeepAlive/PingTimeout/WriteTimeout
+// apply. A lost connection is re-established automatically, each attempt
+// delayed by ReconnectJitter.
 func NewClient(brokerURL string, tlsConfig *tls.Config, clientID string, connectTimeout time.Duration) (mqtt.Client, error) {
 	options := mqtt.NewClientOptions().
 		AddBroker(brokerURL).
 		SetClientID(clientID).
 		SetTLSConfig(tlsConfig).
 		SetConnectTimeout(connectTimeout).
-		SetAutoReconnect(true)
+		SetKeepAlive(KeepAlive).
+		SetPingTimeout(PingTimeout).
+		SetWriteTimeout(WriteTimeout).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(MaxReconnectInterval).
+		SetReconnectingHandler(func(_ mqtt.Client, options *mqtt.ClientOptions) {
+			time.Sleep(ReconnectJitter(options.MaxReconnectInterval))
+		})
 
 	client := mqtt.NewClient(options)
 
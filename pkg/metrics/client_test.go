@@ -0,0 +1,83 @@
+package metrics_test
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/metrics"
+)
+
+// Requirement: EH-F-10
+func TestReconnectJitter_StaysInBoundsAndVaries(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxInterval time.Duration
+		wantBound   time.Duration
+	}{
+		{name: "default interval is capped", maxInterval: metrics.MaxReconnectInterval, wantBound: 10 * time.Second},
+		{name: "short interval bounds the jitter", maxInterval: 2 * time.Second, wantBound: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[time.Duration]bool)
+			for i := 0; i < 100; i++ {
+				delay := metrics.ReconnectJitter(tt.maxInterval)
+				if delay < 0 || delay >= tt.wantBound {
+					t.Fatalf("ReconnectJitter(%v) = %v, want in [0, %v)", tt.maxInterval, delay, tt.wantBound)
+				}
+				seen[delay] = true
+			}
+			// 100 services reconnecting must not all pick the same instant.
+			if len(seen) < 2 {
+				t.Fatalf("ReconnectJitter(%v) returned one value across 100 calls, want jitter", tt.maxInterval)
+			}
+		})
+	}
+}
+
+// Requirement: EH-F-10
+func TestReconnectJitter_NonPositiveIntervalIsZero(t *testing.T) {
+	if got := metrics.ReconnectJitter(0); got != 0 {
+		t.Fatalf("ReconnectJitter(0) = %v, want 0", got)
+	}
+}
+
+// Requirement: EH-F-10
+func TestNewClient_StallingBrokerTimesOut(t *testing.T) {
+	// A listener that accepts connections but never speaks: the TLS
+	// handshake, and so the MQTT CONNECT, never completes.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	const connectTimeout = 500 * time.Millisecond
+	start := time.Now()
+	client, err := metrics.NewClient("tls://"+listener.Addr().String(), &tls.Config{MinVersion: tls.VersionTLS12}, "stall-test", connectTimeout)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		client.Disconnect(0)
+		t.Fatal("NewClient() error = nil, want a timeout against a stalling broker")
+	}
+	if elapsed > 5*connectTimeout {
+		t.Fatalf("NewClient() returned after %v, want roughly %v", elapsed, connectTimeout)
+	}
+}
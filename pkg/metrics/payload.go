@@ -32,13 +32,56 @@ type Counters struct {
 	// ActiveConnections is a point-in-time count of open connections at
 	// publish time (MT-F-04).
 	ActiveConnections int64
+	// DryRunCount is the number of validate-only requests handled in the
+	// interval, kept apart from RequestCount so they do not inflate it.
+	// Only Entry-Hub's /api/validate produces them; every other service
+	// reports 0.
+	DryRunCount int64
+	// DryRunErrorCount is the number of those validate-only requests
+	// that failed validation or were refused.
+	DryRunErrorCount int64
+	// MXLookupCount is the number of email-domain MX checks made in the
+	// interval, answered from cache or DNS; MXRejectionCount those that
+	// found no MX record, and MXLookupFailureCount those DNS could not
+	// answer, which were let through. Only Entry-Hub with
+	// RAM_USB_ENTRY_HUB_REQUIRE_EMAIL_MX set produces them; every other
+	// service reports 0.
+	MXLookupCount        int64
+	MXRejectionCount     int64
+	MXLookupFailureCount int64
+	// DBPoolAcquired, DBPoolIdle and DBPoolMax are the service's
+	// PostgreSQL connection pool's acquired, idle and maximum connections
+	// at publish time, and DBPoolWaitDurationMs the total milliseconds
+	// acquires have spent waiting because no connection was idle. Only
+	// services holding a pool (Database-Vault, Metrics-Collector) produce
+	// them, via pgpool.Stats.Record; every other service reports 0.
+	DBPoolAcquired       int64
+	DBPoolIdle           int64
+	DBPoolMax            int64
+	DBPoolWaitDurationMs int64
+	// DBPoolLeakSuspected reports whether pgpool.LeakDetector suspects a
+	// connection leak at publish time: connections acquired and none idle
+	// for several samples in a row. Always false unless the service's
+	// DATABASE_POOL_STATS_INTERVAL enables pgpool.MonitorStats.
+	DBPoolLeakSuspected bool
+	// HashesInFlight is a point-in-time count of requests computing or
+	// verifying an Argon2id password hash (DV-F-07) at publish time,
+	// each holding 46 MiB of working memory. Only Database-Vault produces
+	// it; every other service reports 0.
+	HashesInFlight int64
+	// AverageEncryptionTimeMs is the mean time, in milliseconds, one
+	// email encryption took (DV-F-04's AES-256-GCM under an HKDF-derived
+	// key). Its JSON name leaves out what is encrypted, since no payload
+	// key may contain "email". Only Database-Vault produces it; every
+	// other service reports 0.
+	AverageEncryptionTimeMs float64
 }
 
 // Payload is the exact JSON shape published to a service's metrics topic
 // every minute (EH-F-10, SS-F-07, DV-F-16, ST-F-12, NM-F-17, CA-F-03).
-// Every field is a count, an average, or a timestamp - never an email,
-// username, node ID, IP address, or any other per-user/per-node value -
-// satisfying each requirement's paired "aggregated statistics only, never
+// Every field is a count, an average, a duration, a flag, or a timestamp -
+// never an email, username, node ID, IP address, or any other
+// per-user/per-node value - satisfying each requirement's paired "aggregated statistics only, never
 // personal data" constraint (EH-F-11, SS-F-08, DV-F-17, ST-F-13, NM-F-18,
 // and CA-F-03's own pairing) by construction: there is no field here a
 // per-user value could be assigned to. No SRS requirement or design
@@ -48,27 +91,56 @@ type Counters struct {
 // documented judgment call, identical across every service that publishes
 // it.
 type Payload struct {
-	Service               string  `json:"service"`
-	Timestamp             string  `json:"timestamp"`
-	RequestCount          int64   `json:"request_count"`
-	ErrorCount            int64   `json:"error_count"`
-	AverageResponseTimeMs float64 `json:"average_response_time_ms"`
-	ActiveConnections     int64   `json:"active_connections"`
+	Service                 string  `json:"service"`
+	Timestamp               string  `json:"timestamp"`
+	RequestCount            int64   `json:"request_count"`
+	ErrorCount              int64   `json:"error_count"`
+	AverageResponseTimeMs   float64 `json:"average_response_time_ms"`
+	ActiveConnections       int64   `json:"active_connections"`
+	DryRunCount             int64   `json:"dry_run_count"`
+	DryRunErrorCount        int64   `json:"dry_run_error_count"`
+	MXLookupCount           int64   `json:"mx_lookup_count"`
+	MXRejectionCount        int64   `json:"mx_rejection_count"`
+	MXLookupFailureCount    int64   `json:"mx_lookup_failure_count"`
+	DBPoolAcquired          int64   `json:"db_pool_acquired"`
+	DBPoolIdle              int64   `json:"db_pool_idle"`
+	DBPoolMax               int64   `json:"db_pool_max"`
+	DBPoolWaitDurationMs    int64   `json:"db_pool_wait_duration_ms"`
+	DBPoolLeakSuspected     bool    `json:"db_pool_leak_suspected"`
+	HashesInFlight          int64   `json:"hashes_in_flight"`
+	AverageEncryptionTimeMs float64 `json:"average_encryption_time_ms"`
 }
 
-// BuildPayload converts serviceName's already-computed counters into the
-// JSON bytes published to TopicFor(serviceName). now is taken as an
-// explicit parameter (rather than read internally via time.Now()) so
-// tests can assert an exact timestamp value.
-func BuildPayload(serviceName string, counters Counters, now time.Time) ([]byte, error) {
-	payload := Payload{
-		Service:               serviceName,
-		Timestamp:             now.UTC().Format(time.RFC3339),
-		RequestCount:          counters.RequestCount,
-		ErrorCount:            counters.ErrorCount,
-		AverageResponseTimeMs: counters.AverageResponseTimeMs,
-		ActiveConnections:     counters.ActiveConnections,
+// NewPayload converts serviceName's already-computed counters into the
+// Payload BuildPayload marshals. Metrics-Collector, which stores its own
+// rows directly instead of publishing them, builds them with it too. now
+// is taken as an explicit parameter (rather than read internally via
+// time.Now()) so tests can assert an exact timestamp value.
+func NewPayload(serviceName string, counters Counters, now time.Time) Payload {
+	return Payload{
+		Service:                 serviceName,
+		Timestamp:               now.UTC().Format(time.RFC3339),
+		RequestCount:            counters.RequestCount,
+		ErrorCount:              counters.ErrorCount,
+		AverageResponseTimeMs:   counters.AverageResponseTimeMs,
+		ActiveConnections:       counters.ActiveConnections,
+		DryRunCount:             counters.DryRunCount,
+		DryRunErrorCount:        counters.DryRunErrorCount,
+		MXLookupCount:           counters.MXLookupCount,
+		MXRejectionCount:        counters.MXRejectionCount,
+		MXLookupFailureCount:    counters.MXLookupFailureCount,
+		DBPoolAcquired:          counters.DBPoolAcquired,
+		DBPoolIdle:              counters.DBPoolIdle,
+		DBPoolMax:               counters.DBPoolMax,
+		DBPoolWaitDurationMs:    counters.DBPoolWaitDurationMs,
+		DBPoolLeakSuspected:     counters.DBPoolLeakSuspected,
+		HashesInFlight:          counters.HashesInFlight,
+		AverageEncryptionTimeMs: counters.AverageEncryptionTimeMs,
 	}
+}
 
-	return json.Marshal(payload)
+// BuildPayload converts serviceName's already-computed counters into the
+// JSON bytes published to TopicFor(serviceName).
+func BuildPayload(serviceName string, counters Counters, now time.Time) ([]byte, error) {
+	return json.Marshal(NewPayload(serviceName, counters, now))
 }
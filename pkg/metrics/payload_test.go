@@ -41,16 +41,29 @@ func TestBuildPayload_NeverContainsPersonalData(t *testing.T) {
 	}
 
 	// The payload's field set is exactly this, and only this - every
-	// field is a count, an average, or a timestamp. Any additional field
-	// (email, username, ip, ssh_public_key, ...) would be a violation of
-	// every service's paired "aggregated statistics only" requirement.
+	// field is a count, an average, a duration, a flag, or a timestamp.
+	// Any additional field (email, username, ip, ssh_public_key, ...)
+	// would be a violation of every service's paired "aggregated
+	// statistics only" requirement.
 	wantFields := map[string]bool{
-		"service":                  true,
-		"timestamp":                true,
-		"request_count":            true,
-		"error_count":              true,
-		"average_response_time_ms": true,
-		"active_connections":       true,
+		"service":                    true,
+		"timestamp":                  true,
+		"request_count":              true,
+		"error_count":                true,
+		"average_response_time_ms":   true,
+		"active_connections":         true,
+		"dry_run_count":              true,
+		"dry_run_error_count":        true,
+		"mx_lookup_count":            true,
+		"mx_rejection_count":         true,
+		"mx_lookup_failure_count":    true,
+		"db_pool_acquired":           true,
+		"db_pool_idle":               true,
+		"db_pool_max":                true,
+		"db_pool_wait_duration_ms":   true,
+		"db_pool_leak_suspected":     true,
+		"hashes_in_flight":           true,
+		"average_encryption_time_ms": true,
 	}
 
 	for name := range fields {
@@ -116,10 +129,22 @@ func TestBuildPayload_CountersRoundTrip(t *testing.T) {
 		{
 			name: "nonzero counters",
 			counters: metrics.Counters{
-				RequestCount:          1000,
-				ErrorCount:            10,
-				AverageResponseTimeMs: 12.34,
-				ActiveConnections:     55,
+				RequestCount:            1000,
+				ErrorCount:              10,
+				AverageResponseTimeMs:   12.34,
+				ActiveConnections:       55,
+				DryRunCount:             300,
+				DryRunErrorCount:        40,
+				MXLookupCount:           250,
+				MXRejectionCount:        12,
+				MXLookupFailureCount:    3,
+				DBPoolAcquired:          4,
+				DBPoolIdle:              6,
+				DBPoolMax:               10,
+				DBPoolWaitDurationMs:    1500,
+				DBPoolLeakSuspected:     true,
+				HashesInFlight:          2,
+				AverageEncryptionTimeMs: 0.25,
 			},
 		},
 	}
@@ -148,6 +173,24 @@ func TestBuildPayload_CountersRoundTrip(t *testing.T) {
 			if payload.ActiveConnections != tt.counters.ActiveConnections {
 				t.Errorf("ActiveConnections = %d, want %d", payload.ActiveConnections, tt.counters.ActiveConnections)
 			}
+			if payload.DryRunCount != tt.counters.DryRunCount || payload.DryRunErrorCount != tt.counters.DryRunErrorCount {
+				t.Errorf("dry-run counts = (%d, %d), want (%d, %d)", payload.DryRunCount, payload.DryRunErrorCount, tt.counters.DryRunCount, tt.counters.DryRunErrorCount)
+			}
+			if payload.MXLookupCount != tt.counters.MXLookupCount || payload.MXRejectionCount != tt.counters.MXRejectionCount || payload.MXLookupFailureCount != tt.counters.MXLookupFailureCount {
+				t.Errorf("mx counts = (%d, %d, %d), want (%d, %d, %d)", payload.MXLookupCount, payload.MXRejectionCount, payload.MXLookupFailureCount, tt.counters.MXLookupCount, tt.counters.MXRejectionCount, tt.counters.MXLookupFailureCount)
+			}
+			if payload.DBPoolAcquired != tt.counters.DBPoolAcquired || payload.DBPoolIdle != tt.counters.DBPoolIdle || payload.DBPoolMax != tt.counters.DBPoolMax || payload.DBPoolWaitDurationMs != tt.counters.DBPoolWaitDurationMs {
+				t.Errorf("db pool gauges = (%d, %d, %d, %d), want (%d, %d, %d, %d)", payload.DBPoolAcquired, payload.DBPoolIdle, payload.DBPoolMax, payload.DBPoolWaitDurationMs, tt.counters.DBPoolAcquired, tt.counters.DBPoolIdle, tt.counters.DBPoolMax, tt.counters.DBPoolWaitDurationMs)
+			}
+			if payload.DBPoolLeakSuspected != tt.counters.DBPoolLeakSuspected {
+				t.Errorf("DBPoolLeakSuspected = %v, want %v", payload.DBPoolLeakSuspected, tt.counters.DBPoolLeakSuspected)
+			}
+			if payload.HashesInFlight != tt.counters.HashesInFlight {
+				t.Errorf("HashesInFlight = %d, want %d", payload.HashesInFlight, tt.counters.HashesInFlight)
+			}
+			if payload.AverageEncryptionTimeMs != tt.counters.AverageEncryptionTimeMs {
+				t.Errorf("AverageEncryptionTimeMs = %v, want %v", payload.AverageEncryptionTimeMs, tt.counters.AverageEncryptionTimeMs)
+			}
 		})
 	}
 }
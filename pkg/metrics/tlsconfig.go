@@ -22,6 +22,16 @@ const OrganizationMQTTBroker = "MQTTBroker"
 // PKI-F-02's organization check on top (mtls.WithOrganization), without
 // touching base's certificate presentation/renewal mechanism, and without
 // this package needing a dependency on pkg/pki itself.
+//
+// The result never resumes a TLS session: ClientSessionCache is cleared
+// even if base carries one, so every reconnect to the broker is a full
+// handshake with a fresh ephemeral key exchange and a fresh PKI-F-02
+// check. A metrics client reconnects at most a few times a day, so
+// resumption would save nothing worth a session ticket outliving the
+// connection it came from. Renegotiation is left at the tls package's
+// never-renegotiate default, and does not exist in TLS 1.3 anyway.
 func TLSConfig(base *tls.Config) *tls.Config {
-	return mtls.WithOrganization(base, OrganizationMQTTBroker)
+	config := mtls.WithOrganization(base, OrganizationMQTTBroker)
+	config.ClientSessionCache = nil
+	return config
 }
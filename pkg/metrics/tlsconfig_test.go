@@ -83,6 +83,26 @@ func TestTLSConfig_AcceptsOnlyMQTTBrokerOrganization(t *testing.T) {
 	}
 }
 
+// Requirement: EH-F-10
+func TestTLSConfig_NeverResumesSessions(t *testing.T) {
+	base := &tls.Config{
+		ServerName:         "localhost",
+		ClientSessionCache: tls.NewLRUClientSessionCache(1),
+	}
+
+	config := metrics.TLSConfig(base)
+
+	if config.ClientSessionCache != nil {
+		t.Fatal("TLSConfig().ClientSessionCache != nil, want session resumption disabled")
+	}
+	if config.Renegotiation != tls.RenegotiateNever {
+		t.Fatalf("TLSConfig().Renegotiation = %v, want tls.RenegotiateNever", config.Renegotiation)
+	}
+	if base.ClientSessionCache == nil {
+		t.Fatal("TLSConfig() cleared base's ClientSessionCache, want base left untouched")
+	}
+}
+
 // startTestBroker starts a bare (no client-cert-required) TLS listener
 // standing in for the MQTT broker, presenting serverCert. It drives each
 // accepted connection's handshake in the background, exactly like
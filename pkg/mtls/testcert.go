@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"math/big"
 	"time"
@@ -65,6 +66,12 @@ func (ca *TestCA) Pool() *x509.CertPool {
 	return ca.pool
 }
 
+// CertPEM returns this test CA's certificate PEM-encoded, for tests that
+// load trust anchors from a file rather than from Pool.
+func (ca *TestCA) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+}
+
 // IssueLeaf signs a leaf certificate for the given organization, usable as
 // either a client or a server certificate in tests. commonName distinguishes
 // certificates within a test; it carries no authorization meaning.
@@ -0,0 +1,54 @@
+// Package pgpool warms a pgx connection pool at startup, for the two
+// RAM-USB services that own a database: Database-Vault (DV-F-08) and
+// Metrics-Collector (MT-F-03). pgxpool.New connects lazily, and the
+// background fill it starts for MinConns is best-effort and unawaited.
+// Without Warm, the first requests after a restart pay for a TCP connect,
+// TLS handshake, and Postgres authentication each, on top of Argon2id
+// (DV-F-07) in Database-Vault's case, and that shows up in RNF-PERF-01's
+// p99.
+//
+// The same two services also check their connection string with
+// RequireTLS before connecting, wait for a database that is still
+// starting with Retry, publish the pool's gauges through StatsOf and
+// Stats.Record, and may run MonitorStats to log the pool's connection
+// counts and flag a suspected connection leak.
+package pgpool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Warm establishes pool's MinConns connections (at least one) before it
+// returns, pinging each, and releases them back to the pool idle. MinConns
+// comes from the connection string's pool_min_conns parameter. An error
+// means the database could not be reached or could not serve that many
+// connections; callers treat it as a startup failure (RD-04), since a
+// pool that cannot open its minimum now will not serve requests either.
+func Warm(ctx context.Context, pool *pgxpool.Pool) error {
+	n := max(int(pool.Config().MinConns), 1)
+
+	conns := make([]*pgxpool.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Release()
+		}
+	}()
+
+	// Holding every acquired connection until all n are open forces the
+	// pool to create n distinct ones, rather than handing the same idle
+	// connection back each time.
+	for i := range n {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("pgpool: open connection %d of %d: %w", i+1, n, err)
+		}
+		conns = append(conns, conn)
+		if err := conn.Ping(ctx); err != nil {
+			return fmt.Errorf("pgpool: ping connection %d of %d: %w", i+1, n, err)
+		}
+	}
+	return nil
+}
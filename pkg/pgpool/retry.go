@@ -0,0 +1,64 @@
+package pgpool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/logging"
+)
+
+// Backoff bounds for Retry: the first retry comes quickly, since
+// a database container that is merely still starting is usually ready
+// within a second or two, and later retries back off to avoid hammering
+// one that is genuinely down.
+const (
+	initialRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 5 * time.Second
+)
+
+// Retry calls attempt until it returns nil, retrying with exponential
+// backoff for up to timeout. Opening a database fails immediately if it
+// is not yet accepting connections, which is routine in a containerized
+// deploy where the database starts alongside, not strictly before, the
+// service; retrying lets startup ride out that race instead of exiting
+// and relying on a restart policy. Once timeout is spent (or ctx is done)
+// the last attempt's error is returned, so a database that never comes
+// up still fails startup (RD-04).
+func Retry(ctx context.Context, timeout time.Duration, attempt func() error) error {
+	return retry(ctx, timeout, initialRetryBackoff, maxRetryBackoff, attempt)
+}
+
+// retry runs attempt until it returns nil, waiting initial between the
+// first two attempts and doubling the wait each time after, capped at
+// maxBackoff. It gives up, returning the last attempt's error, once the
+// next wait would end past timeout or ctx is done.
+func retry(ctx context.Context, timeout, initial, maxBackoff time.Duration, attempt func() error) error {
+	deadline := time.Now().Add(timeout)
+	backoff := initial
+
+	for {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("pgpool: database not ready after %s: %w", timeout, err)
+		}
+
+		slog.Warn("pgpool: database not ready, retrying",
+			"error", logging.Sanitize(err.Error()), "retry_in", backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("pgpool: %w while waiting for database: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
@@ -0,0 +1,63 @@
+package pgpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// errNotReady stands in for a connection attempt's error while the
+// database is still starting.
+var errNotReady = errors.New("connection refused")
+
+// TestRetry_SucceedsOnceDatabaseBecomesReady simulates a database that
+// starts accepting connections only after a delay.
+func TestRetry_SucceedsOnceDatabaseBecomesReady(t *testing.T) {
+	readyAt := time.Now().Add(30 * time.Millisecond)
+	attempts := 0
+
+	err := retry(context.Background(), time.Second, time.Millisecond, 10*time.Millisecond, func() error {
+		attempts++
+		if time.Now().Before(readyAt) {
+			return errNotReady
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retry() error = %v, want nil", err)
+	}
+	if attempts < 2 {
+		t.Fatalf("attempts = %d, want at least 2 (the first must have failed)", attempts)
+	}
+}
+
+// TestRetry_GivesUpAfterTimeout verifies a database that never comes up
+// still fails startup, with the last attempt's error preserved.
+func TestRetry_GivesUpAfterTimeout(t *testing.T) {
+	start := time.Now()
+
+	err := retry(context.Background(), 50*time.Millisecond, time.Millisecond, 10*time.Millisecond, func() error {
+		return errNotReady
+	})
+	if !errors.Is(err, errNotReady) {
+		t.Fatalf("retry() error = %v, want wrapping errNotReady", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("retry() took %v, want it to stop near its 50ms timeout", elapsed)
+	}
+}
+
+// TestRetry_StopsWhenContextIsDone verifies a shutdown signal during
+// startup is not held up by the retry loop.
+func TestRetry_StopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := retry(ctx, time.Minute, time.Second, time.Second, func() error {
+		return errNotReady
+	})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errNotReady) {
+		t.Fatalf("retry() error = %v, want wrapping context.Canceled and errNotReady", err)
+	}
+}
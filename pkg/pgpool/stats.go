@@ -0,0 +1,137 @@
+package pgpool
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Verryx-02/RAM-USB/pkg/metrics"
+)
+
+// LeakAlertKey is the "alert" attribute value on the log line
+// MonitorStats writes when a pool starts looking leaked, so log-based
+// alerting can match it without parsing the message, same convention as
+// pkg/metrics.FailureAlertKey.
+const LeakAlertKey = "database_pool_leak_suspected"
+
+// Stats is the part of a *pgxpool.Stat MonitorStats logs, LeakDetector
+// judges, and Record publishes.
+type Stats struct {
+	Acquired int32
+	Idle     int32
+	Total    int32
+	Max      int32
+	// Wait is the total time acquires have spent waiting because no
+	// connection was idle, since the pool was created.
+	Wait time.Duration
+}
+
+// StatsOf reads pool's current Stats.
+func StatsOf(pool *pgxpool.Pool) Stats {
+	stat := pool.Stat()
+	return Stats{
+		Acquired: stat.AcquiredConns(),
+		Idle:     stat.IdleConns(),
+		Total:    stat.TotalConns(),
+		Max:      stat.MaxConns(),
+		Wait:     stat.EmptyAcquireWaitTime(),
+	}
+}
+
+// Record copies s into counters' DBPool gauges, for a service publishing
+// its pool's state in its metrics payload (DV-F-16, MT-F-04).
+func (s Stats) Record(counters *metrics.Counters) {
+	counters.DBPoolAcquired = int64(s.Acquired)
+	counters.DBPoolIdle = int64(s.Idle)
+	counters.DBPoolMax = int64(s.Max)
+	counters.DBPoolWaitDurationMs = s.Wait.Milliseconds()
+}
+
+// LeakDetector flags the signature of a connection leak: connections
+// acquired and none idle, sample after sample. Both services' queries
+// finish in milliseconds, so a pool with no idle connection over several
+// samples taken a minute apart is either saturated far beyond its design
+// load or holding connections nobody will release; either way an operator
+// should look.
+//
+// Observe is meant to be called from one goroutine (MonitorStats's);
+// Suspected may be called from any other, e.g. a metrics publisher.
+type LeakDetector struct {
+	// Samples is how many consecutive leak-shaped observations make a
+	// suspected leak.
+	Samples int
+
+	streak    int
+	suspected atomic.Bool
+}
+
+// Observe records s and reports whether a leak is suspected now, and
+// whether this observation is the one that started the suspicion, so the
+// caller warns once per episode rather than on every sample. Any
+// observation with an idle connection, or none acquired, ends the
+// episode.
+func (d *LeakDetector) Observe(s Stats) (suspected, started bool) {
+	if s.Idle > 0 || s.Acquired == 0 {
+		d.streak = 0
+		d.suspected.Store(false)
+		return false, false
+	}
+	d.streak++
+	if d.streak < d.Samples {
+		return false, false
+	}
+	started = !d.suspected.Swap(true)
+	return true, started
+}
+
+// Suspected reports whether the latest observation left a leak
+// suspected. A nil LeakDetector, for a pool nobody monitors, never
+// suspects one.
+func (d *LeakDetector) Suspected() bool {
+	return d != nil && d.suspected.Load()
+}
+
+// Record copies d's suspicion into counters, for a service publishing it
+// next to its pool's gauges (Stats.Record).
+func (d *LeakDetector) Record(counters *metrics.Counters) {
+	counters.DBPoolLeakSuspected = d.Suspected()
+}
+
+// MonitorStats logs pool's connection counts every interval until ctx is
+// done, feeding each sample to detector. It logs an error with
+// "alert"=LeakAlertKey when detector starts suspecting a leak, and an
+// info line when the suspicion clears; the caller may publish
+// detector.Suspected() meanwhile.
+func MonitorStats(ctx context.Context, pool *pgxpool.Pool, interval time.Duration, detector *LeakDetector) {
+	monitorStats(ctx, func() Stats { return StatsOf(pool) }, interval, detector, slog.Default())
+}
+
+// monitorStats is MonitorStats over any stats source, for tests.
+func monitorStats(ctx context.Context, stats func() Stats, interval time.Duration, detector *LeakDetector, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := stats()
+			logger.Info("pgpool: connection pool stats",
+				"acquired", s.Acquired, "idle", s.Idle, "total", s.Total, "max", s.Max)
+
+			wasSuspected := detector.Suspected()
+			suspected, started := detector.Observe(s)
+			switch {
+			case started:
+				logger.Error("pgpool: connection leak suspected, no idle connections for several samples",
+					"alert", LeakAlertKey, "acquired", s.Acquired, "max", s.Max)
+			case wasSuspected && !suspected:
+				logger.Info("pgpool: connection pool has idle connections again")
+			}
+		}
+	}
+}
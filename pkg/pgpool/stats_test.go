@@ -0,0 +1,138 @@
+package pgpool
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/metrics"
+)
+
+// Requirement: DV-F-08
+// Requirement: MT-F-03
+func TestLeakDetector_Observe(t *testing.T) {
+	leaked := Stats{Acquired: 4, Idle: 0, Total: 4, Max: 4}
+	healthy := Stats{Acquired: 1, Idle: 3, Total: 4, Max: 4}
+	quiet := Stats{Acquired: 0, Idle: 0, Total: 0, Max: 4}
+
+	steps := []struct {
+		stats         Stats
+		wantSuspected bool
+		wantStarted   bool
+	}{
+		{stats: leaked},
+		{stats: leaked},
+		{stats: leaked, wantSuspected: true, wantStarted: true},
+		{stats: leaked, wantSuspected: true},
+		{stats: healthy},
+		{stats: leaked},
+		{stats: quiet},
+		{stats: leaked},
+		{stats: leaked},
+		{stats: leaked, wantSuspected: true, wantStarted: true},
+	}
+
+	d := &LeakDetector{Samples: 3}
+	for i, step := range steps {
+		suspected, started := d.Observe(step.stats)
+		if suspected != step.wantSuspected || started != step.wantStarted {
+			t.Fatalf("step %d: Observe(%+v) = (%v, %v), want (%v, %v)",
+				i, step.stats, suspected, started, step.wantSuspected, step.wantStarted)
+		}
+		if d.Suspected() != step.wantSuspected {
+			t.Fatalf("step %d: Suspected() = %v, want %v", i, d.Suspected(), step.wantSuspected)
+		}
+	}
+}
+
+// Requirement: DV-F-16
+// Requirement: MT-F-04
+func TestLeakDetector_Record(t *testing.T) {
+	var unmonitored *LeakDetector
+	counters := metrics.Counters{DBPoolLeakSuspected: true}
+	unmonitored.Record(&counters)
+	if counters.DBPoolLeakSuspected {
+		t.Fatal("nil LeakDetector recorded a suspected leak")
+	}
+
+	d := &LeakDetector{Samples: 1}
+	d.Observe(Stats{Acquired: 4, Idle: 0, Total: 4, Max: 4})
+	d.Record(&counters)
+	if !counters.DBPoolLeakSuspected {
+		t.Fatal("DBPoolLeakSuspected = false after a leak-shaped sample, want true")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe to write from monitorStats's
+// goroutine while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Requirement: DV-F-08
+func TestMonitorStats_WarnsOnLeakedConnection(t *testing.T) {
+	var logBuf syncBuffer
+	logger := slog.New(slog.NewTextHandler(&logBuf, nil))
+
+	// One connection acquired and never released, nothing idle.
+	leaked := func() Stats { return Stats{Acquired: 1, Idle: 0, Total: 1, Max: 4} }
+
+	detector := &LeakDetector{Samples: 2}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitorStats(ctx, leaked, 5*time.Millisecond, detector, logger)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(logBuf.String(), "alert="+LeakAlertKey) {
+		if time.Now().After(deadline) {
+			t.Fatalf("no leak warning logged; log = %s", logBuf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := strings.Count(logBuf.String(), "alert="+LeakAlertKey); got != 1 {
+		t.Fatalf("leak warning logged %d times, want once per episode", got)
+	}
+	// Read while monitorStats keeps observing, as a metrics publisher
+	// would.
+	if !detector.Suspected() {
+		t.Fatal("Suspected() = false after the leak warning, want true")
+	}
+}
+
+// Requirement: DV-F-16
+// Requirement: MT-F-04
+func TestStats_Record(t *testing.T) {
+	s := Stats{Acquired: 3, Idle: 2, Total: 5, Max: 10, Wait: 1500 * time.Millisecond}
+
+	var counters metrics.Counters
+	s.Record(&counters)
+
+	if counters.DBPoolAcquired != 3 || counters.DBPoolIdle != 2 || counters.DBPoolMax != 10 || counters.DBPoolWaitDurationMs != 1500 {
+		t.Fatalf("db pool gauges = (%d, %d, %d, %d), want (3, 2, 10, 1500)",
+			counters.DBPoolAcquired, counters.DBPoolIdle, counters.DBPoolMax, counters.DBPoolWaitDurationMs)
+	}
+}
@@ -0,0 +1,37 @@
+package pgpool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrPlaintextConnection means a connection string's sslmode (disable,
+// allow, or prefer, the last being libpq's and pgx's default when sslmode
+// is absent) lets the connection, password included, travel unencrypted.
+var ErrPlaintextConnection = errors.New("pgpool: connection string allows an unencrypted connection; use sslmode=require, verify-ca, or verify-full")
+
+// RequireTLS checks that every connection databaseURL describes is
+// encrypted: sslmode must be require or stricter, in the URL itself or in
+// PGSSLMODE. Callers run it before anything dials the database, the
+// schema migration included, and fail startup on an error (RD-04).
+//
+// pgconn.ParseConfig expands sslmode into a primary config and fallbacks,
+// "prefer" becoming TLS-then-plaintext, so checking that none of them
+// lacks a TLSConfig covers every mode without matching on its name.
+func RequireTLS(databaseURL string) error {
+	config, err := pgconn.ParseConfig(databaseURL)
+	if err != nil {
+		return fmt.Errorf("pgpool: parse connection string: %w", err)
+	}
+	if config.TLSConfig == nil {
+		return ErrPlaintextConnection
+	}
+	for _, fallback := range config.Fallbacks {
+		if fallback.TLSConfig == nil {
+			return ErrPlaintextConnection
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package pgpool_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Verryx-02/RAM-USB/pkg/pgpool"
+)
+
+// Requirement: DV-F-08
+// Requirement: MT-F-03
+func TestRequireTLS(t *testing.T) {
+	t.Setenv("PGSSLMODE", "")
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "disable", url: "postgres://u:p@db:5432/app?sslmode=disable", wantErr: pgpool.ErrPlaintextConnection},
+		{name: "allow", url: "postgres://u:p@db:5432/app?sslmode=allow", wantErr: pgpool.ErrPlaintextConnection},
+		{name: "prefer", url: "postgres://u:p@db:5432/app?sslmode=prefer", wantErr: pgpool.ErrPlaintextConnection},
+		{name: "absent defaults to prefer", url: "postgres://u:p@db:5432/app", wantErr: pgpool.ErrPlaintextConnection},
+		{name: "require", url: "postgres://u:p@db:5432/app?sslmode=require"},
+		{name: "verify-full", url: "postgres://u:p@db:5432/app?sslmode=verify-full"},
+		{name: "keyword form", url: "host=db user=u dbname=app sslmode=require"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := pgpool.RequireTLS(tt.url); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequireTLS(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Requirement: DV-F-08
+func TestRequireTLS_MalformedConnectionString(t *testing.T) {
+	err := pgpool.RequireTLS("postgres://u:p@db:notaport/app?sslmode=require")
+	if err == nil || errors.Is(err, pgpool.ErrPlaintextConnection) {
+		t.Fatalf("RequireTLS() error = %v, want a parse error", err)
+	}
+}
@@ -50,10 +50,12 @@ package pki
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
-	"os"
 
 	stepca "github.com/smallstep/certificates/ca"
+
+	"github.com/Verryx-02/RAM-USB/pkg/secrets"
 )
 
 // BootstrapTokenEnvVar names the environment variable holding this
@@ -71,13 +73,17 @@ const BootstrapTokenEnvVar = "RAM_USB_CA_BOOTSTRAP_TOKEN" //nolint:gosec // an e
 var ErrBootstrapTokenMissing = errors.New("pki: bootstrap token missing")
 
 // LoadBootstrapToken reads this service's bootstrap token from
-// BootstrapTokenEnvVar. It performs no further validation of the token's
+// BootstrapTokenEnvVar, or from the file named by BootstrapTokenEnvVar+
+// "_FILE" if that is set (pkg/secrets). It performs no further validation of the token's
 // shape — a malformed or expired token is surfaced later, as an error
 // from NewServer/NewClient, by the Certificate-Authority itself refusing
 // to sign.
 func LoadBootstrapToken() (string, error) {
-	token, ok := os.LookupEnv(BootstrapTokenEnvVar)
-	if !ok || token == "" {
+	token, ok, err := secrets.Lookup(BootstrapTokenEnvVar)
+	if err != nil {
+		return "", fmt.Errorf("pki: %w", err)
+	}
+	if !ok {
 		return "", ErrBootstrapTokenMissing
 	}
 	return token, nil
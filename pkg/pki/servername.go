@@ -1,6 +1,7 @@
 package pki
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
@@ -10,9 +11,11 @@ import (
 
 // dialTimeout mirrors github.com/smallstep/certificates@v0.30.2/ca/
 // tls.go's own createDefaultDialer default (30 * time.Second) - used only
-// by ForceServerName, to keep its replacement dial behavior equivalent to
-// the vendored SDK's default instead of falling back to net.Dialer's zero
-// value (no timeout at all).
+// by ForceServerName, as both its TCP-connect and its TLS-handshake bound,
+// to keep its replacement dial behavior close to the vendored SDK's
+// default instead of falling back to net.Dialer's zero value (no timeout
+// at all). A caller that needs faster failure detection passes its own
+// bounds to ForceServerNameWithTimeouts instead.
 const dialTimeout = 30 * time.Second
 
 // ClientTLSConfig returns a clone of base (see (*tls.Config).Clone; base
@@ -69,21 +72,35 @@ func ClientTLSConfig(base *tls.Config, organization string) *tls.Config {
 // outside package ca). Mutating only TLSClientConfig would therefore be a
 // silent no-op for the real dial.
 //
-// ForceServerName instead replaces DialTLSContext outright, with a
-// crypto/tls.Dialer (the modern context-aware stdlib replacement for the
-// vendored SDK's own tls.DialWithDialer+manual-deadline-plumbing dialer)
-// built from client.Transport.TLSClientConfig, cloned via
-// ClientTLSConfig. That field IS reachable, and is confirmed equivalent
-// in content to the SDK's own internal clone for every property this
-// matters for: both are populated, at getClientTLSConfig call time, from
-// the same *tls.Config object before it is cloned into the SDK's private
-// mutableTLSConfig (RootCAs and the immutable CA root are added to that
-// shared object directly, before the clone), and both carry the same
-// GetClientCertificate function value, so certificate renewal (driven by
-// the SDK's own *TLSRenewer, whose state lives outside any single
-// *tls.Config - see this package's other callers' Clone() safety notes)
-// keeps working on the replacement dialer exactly as before.
+// ForceServerName instead replaces DialTLSContext outright, through
+// ForceServerNameWithTimeouts, with dialTLSContext: a net.Dialer connect
+// followed by a tls.Client handshake under its own bound, both bounded
+// here by dialTimeout. Its *tls.Config is
+// client.Transport.TLSClientConfig, cloned via ClientTLSConfig. That field
+// IS reachable, and is confirmed equivalent in content to the SDK's own
+// internal clone for every property this matters for: both are populated,
+// at getClientTLSConfig call time, from the same *tls.Config object before
+// it is cloned into the SDK's private mutableTLSConfig (RootCAs and the
+// immutable CA root are added to that shared object directly, before the
+// clone), and both carry the same GetClientCertificate function value, so
+// certificate renewal (driven by the SDK's own *TLSRenewer, whose state
+// lives outside any single *tls.Config - see this package's other callers'
+// Clone() safety notes) keeps working on the replacement dialer exactly as
+// before.
 func ForceServerName(client *http.Client, organization string) error {
+	return ForceServerNameWithTimeouts(client, organization, dialTimeout, dialTimeout)
+}
+
+// ForceServerNameWithTimeouts is ForceServerName with caller-chosen bounds
+// on the replacement dialer: dial bounds the TCP connect alone, and
+// handshake bounds the TLS handshake alone, so a peer that accepts the
+// TCP connection but never completes the handshake (a half-open or
+// wedged listener) fails after handshake, not after the caller's whole
+// request timeout. Either bound still yields to an earlier deadline on the
+// request's own context. handshake is also set as the Transport's own
+// TLSHandshakeTimeout, which is what applies if no DialTLSContext is
+// replaced.
+func ForceServerNameWithTimeouts(client *http.Client, organization string, dial, handshake time.Duration) error {
 	transport, ok := client.Transport.(*http.Transport)
 	if !ok {
 		return fmt.Errorf("pki: client.Transport is %T, want *http.Transport", client.Transport)
@@ -91,6 +108,7 @@ func ForceServerName(client *http.Client, organization string) error {
 
 	cfg := ClientTLSConfig(transport.TLSClientConfig, organization)
 	transport.TLSClientConfig = cfg
+	transport.TLSHandshakeTimeout = handshake
 
 	// Only replace DialTLSContext if NewClient's underlying SDK call set
 	// one in the first place (it always does today, but this keeps
@@ -98,12 +116,32 @@ func ForceServerName(client *http.Client, organization string) error {
 	// stops setting it, in which case leaving TLSClientConfig set above is
 	// already sufficient).
 	if transport.DialTLSContext != nil {
-		dialer := &tls.Dialer{
-			NetDialer: &net.Dialer{Timeout: dialTimeout},
-			Config:    cfg,
-		}
-		transport.DialTLSContext = dialer.DialContext
+		transport.DialTLSContext = dialTLSContext(&net.Dialer{Timeout: dial}, cfg, handshake)
 	}
 
 	return nil
 }
+
+// dialTLSContext returns an http.Transport.DialTLSContext that connects
+// with netDialer, then runs the TLS handshake under its own handshake
+// bound. crypto/tls.Dialer cannot express this split: it applies its
+// NetDialer's Timeout to the connect and the handshake as a whole.
+func dialTLSContext(netDialer *net.Dialer, cfg *tls.Config, handshake time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		rawConn, err := netDialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		handshakeCtx, cancel := context.WithTimeout(ctx, handshake)
+		defer cancel()
+
+		conn := tls.Client(rawConn, cfg)
+		if err := conn.HandshakeContext(handshakeCtx); err != nil {
+			_ = rawConn.Close()
+			return nil, err
+		}
+
+		return conn, nil
+	}
+}
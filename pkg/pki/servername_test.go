@@ -1,12 +1,16 @@
 package pki
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Verryx-02/RAM-USB/pkg/mtls"
 )
@@ -210,3 +214,64 @@ func TestForceServerName_ReplacesDialTLSContext(t *testing.T) {
 		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
 	}
 }
+
+// Requirement: PKI-F-02
+//
+// A peer that accepts the TCP connection but never answers the TLS
+// ClientHello must fail the dial after ForceServerNameWithTimeouts'
+// handshake bound, not after the request's own (much longer) deadline.
+func TestForceServerNameWithTimeouts_HandshakeTimesOut(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v, want nil", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		accepted <- conn
+	}()
+	t.Cleanup(func() {
+		select {
+		case conn := <-accepted:
+			_ = conn.Close()
+		default:
+		}
+	})
+
+	dialer := &tls.Dialer{Config: &tls.Config{MinVersion: tls.VersionTLS13}}
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS13},
+		DialTLSContext:  dialer.DialContext,
+	}
+	client := &http.Client{Transport: transport}
+
+	const handshake = 100 * time.Millisecond
+	if err := ForceServerNameWithTimeouts(client, "SecuritySwitch", time.Second, handshake); err != nil {
+		t.Fatalf("ForceServerNameWithTimeouts() error = %v, want nil", err)
+	}
+	if transport.TLSHandshakeTimeout != handshake {
+		t.Fatalf("TLSHandshakeTimeout = %v, want %v", transport.TLSHandshakeTimeout, handshake)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := transport.DialTLSContext(ctx, "tcp", listener.Addr().String())
+	elapsed := time.Since(start)
+	if err == nil {
+		_ = conn.Close()
+		t.Fatal("DialTLSContext() error = nil, want a handshake timeout against a silent peer")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("DialTLSContext() error = %v, want wrapping context.DeadlineExceeded", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("DialTLSContext() took %v, want close to the %v handshake bound", elapsed, handshake)
+	}
+}
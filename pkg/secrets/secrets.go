@@ -0,0 +1,47 @@
+// Package secrets reads a secret configuration value either inline from an
+// environment variable or, following the Docker/Kubernetes secrets
+// convention, from a file whose path is given by the same variable name
+// suffixed with FileSuffix (e.g. RAM_USB_PASSWORD_PEPPER_FILE). The file
+// form keeps the secret itself out of the process environment, which is
+// readable from /proc/<pid>/environ and `docker inspect`. It backs
+// DV-F-05/DV-F-06's "configurable source" for the master key and pepper,
+// and is reused for every other secret a service reads at startup
+// (database URLs carrying credentials, the CA bootstrap token, the
+// Headscale API key).
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileSuffix is appended to a secret's environment variable name to form
+// the name of the variable holding the path of a file containing it.
+const FileSuffix = "_FILE"
+
+// Lookup returns the secret named name. If name+FileSuffix is set and
+// non-empty, the file at that path is read and its content returned, with
+// one trailing newline (as written by `echo` or most editors) removed;
+// this takes precedence over name itself. Otherwise name is read from the
+// environment directly.
+//
+// ok is false if neither source yields a non-empty value, leaving the
+// caller to decide whether that is an error - every current caller treats
+// it as one (RD-04). err is non-nil only if name+FileSuffix is set but its
+// file cannot be read; Lookup never falls back to the inline variable in
+// that case, since an operator who configured a file clearly meant it to
+// be used.
+func Lookup(name string) (value string, ok bool, err error) {
+	if path, set := os.LookupEnv(name + FileSuffix); set && path != "" {
+		content, err := os.ReadFile(path) //nolint:gosec // path is operator-supplied configuration, not request input
+		if err != nil {
+			return "", false, fmt.Errorf("secrets: read %s%s: %w", name, FileSuffix, err)
+		}
+		value = strings.TrimSuffix(strings.TrimSuffix(string(content), "\n"), "\r")
+		return value, value != "", nil
+	}
+
+	value, set := os.LookupEnv(name)
+	return value, set && value != "", nil
+}
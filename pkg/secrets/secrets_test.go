@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testVar = "RAM_USB_SECRETS_TEST_VALUE"
+
+// writeSecretFile writes content to a per-test file and returns its path.
+func writeSecretFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+// Requirement: DV-F-05
+// Requirement: DV-F-06
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		inline    string
+		file      *string
+		wantValue string
+		wantOK    bool
+		wantErr   bool
+	}{
+		{name: "neither set", wantOK: false},
+		{name: "inline only", inline: "inline-value", wantValue: "inline-value", wantOK: true},
+		{name: "file only", file: ptr("file-value"), wantValue: "file-value", wantOK: true},
+		{name: "file takes precedence over inline", inline: "inline-value", file: ptr("file-value"), wantValue: "file-value", wantOK: true},
+		{name: "one trailing newline is trimmed", file: ptr("file-value\n"), wantValue: "file-value", wantOK: true},
+		{name: "trailing CRLF is trimmed", file: ptr("file-value\r\n"), wantValue: "file-value", wantOK: true},
+		{name: "inner whitespace is kept", file: ptr(" a b \n"), wantValue: " a b ", wantOK: true},
+		{name: "empty file is unset", inline: "inline-value", file: ptr(""), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(testVar, tt.inline)
+			t.Setenv(testVar+FileSuffix, "")
+			if tt.file != nil {
+				t.Setenv(testVar+FileSuffix, writeSecretFile(t, *tt.file))
+			}
+
+			value, ok, err := Lookup(testVar)
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+			if ok != tt.wantOK || value != tt.wantValue {
+				t.Fatalf("Lookup() = (%q, %v), want (%q, %v)", value, ok, tt.wantValue, tt.wantOK)
+			}
+		})
+	}
+}
+
+// Requirement: DV-F-05
+// Requirement: DV-F-06
+func TestLookup_UnreadableFileFailsWithoutFallback(t *testing.T) {
+	t.Setenv(testVar, "inline-value")
+	t.Setenv(testVar+FileSuffix, filepath.Join(t.TempDir(), "missing"))
+
+	value, ok, err := Lookup(testVar)
+	if err == nil {
+		t.Fatalf("Lookup() = (%q, %v, nil), want an error for an unreadable file", value, ok)
+	}
+	if ok || value != "" {
+		t.Fatalf("Lookup() = (%q, %v), want no value (never the inline fallback)", value, ok)
+	}
+}
+
+func ptr(s string) *string { return &s }
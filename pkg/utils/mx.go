@@ -0,0 +1,166 @@
+// Package utils holds small network helpers with no service-specific
+// state: today, the email-domain MX-record check Entry-Hub's optional
+// RAM_USB_ENTRY_HUB_REQUIRE_EMAIL_MX setting relies on (EH-F-04).
+//
+// HasMXRecord is the one-call form, failing open on any DNS error. A
+// caller that must tell a domain without MX records apart from a DNS
+// failure (Entry-Hub counts the two separately) uses an MXChecker of its
+// own instead.
+package utils
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMXLookupTimeout bounds one MX lookup made by HasMXRecord, and
+// DefaultMXCacheTTL is how long its answer is reused. A registration
+// waits on the lookup, so the timeout is short; MX records rarely
+// change, so the TTL is long.
+const (
+	DefaultMXLookupTimeout = 2 * time.Second
+	DefaultMXCacheTTL      = time.Hour
+)
+
+// maxMXCacheEntries bounds MXChecker's cache. Reaching it evicts the
+// least recently used entry, so a flood of distinct throwaway domains
+// pushes out only other rarely-seen domains, never the handful of common
+// ones real users register with.
+const maxMXCacheEntries = 10000
+
+// MXResolver is the one *net.Resolver method MXChecker uses, so tests can
+// supply a hand-written fake instead of real DNS.
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// MXChecker answers whether an email domain publishes at least one MX
+// record, caching each definitive answer (records found, or the domain or
+// its MX set not existing) for a TTL. Domains that only accept mail
+// through RFC 5321's implicit MX, an A record with no MX, are treated as
+// having none: the check exists to turn away throwaway domains, and an
+// operator who enables it accepts that trade-off.
+type MXChecker struct {
+	resolver MXResolver
+	timeout  time.Duration
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // of *mxCacheEntry
+	lru     *list.List               // most recently used at the front
+}
+
+// mxCacheEntry is one cached MXChecker answer.
+type mxCacheEntry struct {
+	domain    string
+	hasMX     bool
+	expiresAt time.Time
+}
+
+// NewMXChecker returns an MXChecker resolving through resolver, bounding
+// each lookup by timeout and caching answers for ttl.
+func NewMXChecker(resolver MXResolver, timeout, ttl time.Duration) *MXChecker {
+	return &MXChecker{
+		resolver: resolver,
+		timeout:  timeout,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// defaultMXChecker backs HasMXRecord.
+var defaultMXChecker = NewMXChecker(net.DefaultResolver, DefaultMXLookupTimeout, DefaultMXCacheTTL)
+
+// HasMXRecord reports whether domain publishes at least one MX record,
+// through the system resolver with DefaultMXLookupTimeout and a shared
+// cache kept for DefaultMXCacheTTL. It fails open: a lookup that times
+// out or otherwise gets no definitive answer reports true, so a DNS
+// outage never turns a valid address away.
+func HasMXRecord(domain string) bool {
+	hasMX, err := defaultMXChecker.HasMX(context.Background(), domain, time.Now())
+	return hasMX || err != nil
+}
+
+// HasMX reports whether domain has at least one MX record as of now. err
+// is non-nil only when DNS gave no definitive answer (a timeout, or a
+// resolver failure); such an answer is not cached, and callers are
+// expected to fail open on it.
+func (c *MXChecker) HasMX(ctx context.Context, domain string, now time.Time) (bool, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if hasMX, ok := c.cached(domain, now); ok {
+		return hasMX, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	records, err := c.resolver.LookupMX(lookupCtx, domain+".")
+
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil:
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		records = nil
+	default:
+		return false, err
+	}
+
+	hasMX := false
+	for _, record := range records {
+		// RFC 7505's null MX ("." at any preference) declares that the
+		// domain accepts no mail at all.
+		if record.Host != "." && record.Host != "" {
+			hasMX = true
+			break
+		}
+	}
+
+	c.store(domain, hasMX, now.Add(c.ttl))
+	return hasMX, nil
+}
+
+// cached returns domain's unexpired cached answer, marking it recently
+// used, and drops it if it has expired.
+func (c *MXChecker) cached(domain string, now time.Time) (hasMX, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.entries[domain]
+	if !found {
+		return false, false
+	}
+	entry := elem.Value.(*mxCacheEntry)
+	if !now.Before(entry.expiresAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, domain)
+		return false, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.hasMX, true
+}
+
+// store caches domain's answer until expiresAt, evicting the least
+// recently used entry if the cache is full.
+func (c *MXChecker) store(domain string, hasMX bool, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, found := c.entries[domain]; found {
+		entry := elem.Value.(*mxCacheEntry)
+		entry.hasMX, entry.expiresAt = hasMX, expiresAt
+		c.lru.MoveToFront(elem)
+		return
+	}
+	for c.lru.Len() >= maxMXCacheEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*mxCacheEntry).domain)
+	}
+	c.entries[domain] = c.lru.PushFront(&mxCacheEntry{domain: domain, hasMX: hasMX, expiresAt: expiresAt})
+}
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeMXResolver is a hand-written fake of MXResolver (CONTRIBUTING.md
+// §7.5): it answers from records, returns NXDOMAIN for any other domain,
+// or fails every lookup with err.
+type fakeMXResolver struct {
+	records map[string][]*net.MX
+	err     error
+	calls   int
+}
+
+func (f *fakeMXResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if records, ok := f.records[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func newFakeMXResolver() *fakeMXResolver {
+	return &fakeMXResolver{records: map[string][]*net.MX{
+		"example.com.":    {{Host: "mx1.example.com.", Pref: 10}},
+		"nullmx.example.": {{Host: ".", Pref: 0}},
+	}}
+}
+
+// Requirement: EH-F-04
+func TestMXChecker_HasMX(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{domain: "example.com", want: true},
+		{domain: "EXAMPLE.com.", want: true},
+		{domain: "no-mx.example", want: false},
+		{domain: "nullmx.example", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			checker := NewMXChecker(newFakeMXResolver(), time.Second, time.Hour)
+			got, err := checker.HasMX(context.Background(), tt.domain, now)
+			if err != nil {
+				t.Fatalf("HasMX(%q) error = %v", tt.domain, err)
+			}
+			if got != tt.want {
+				t.Fatalf("HasMX(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+// Requirement: EH-F-04
+func TestMXChecker_CachesDefinitiveAnswersOnly(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	resolver := newFakeMXResolver()
+	checker := NewMXChecker(resolver, time.Second, time.Hour)
+
+	for _, at := range []time.Time{now, now.Add(59 * time.Minute)} {
+		if _, err := checker.HasMX(context.Background(), "example.com", at); err != nil {
+			t.Fatalf("HasMX() error = %v", err)
+		}
+	}
+	if resolver.calls != 1 {
+		t.Fatalf("resolver called %d times within TTL, want 1", resolver.calls)
+	}
+	if _, err := checker.HasMX(context.Background(), "example.com", now.Add(time.Hour)); err != nil {
+		t.Fatalf("HasMX() error = %v", err)
+	}
+	if resolver.calls != 2 {
+		t.Fatalf("resolver called %d times after TTL, want 2", resolver.calls)
+	}
+
+	resolver.err = &net.DNSError{Err: "i/o timeout", Name: "other.example.", IsTimeout: true}
+	for range 2 {
+		if _, err := checker.HasMX(context.Background(), "other.example", now); err == nil {
+			t.Fatal("HasMX() error = nil on a DNS timeout")
+		}
+	}
+	if resolver.calls != 4 {
+		t.Fatalf("resolver called %d times, want a failed lookup never cached", resolver.calls)
+	}
+}
+
+// Requirement: EH-F-04
+func TestMXChecker_EvictsLeastRecentlyUsedWhenFull(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	resolver := newFakeMXResolver()
+	checker := NewMXChecker(resolver, time.Second, time.Hour)
+	check := func(domain string) {
+		t.Helper()
+		if _, err := checker.HasMX(context.Background(), domain, now); err != nil {
+			t.Fatalf("HasMX(%q) error = %v", domain, err)
+		}
+	}
+
+	// Fill the cache: example.com first, then throwaway domains.
+	check("example.com")
+	for i := range maxMXCacheEntries - 1 {
+		check(fmt.Sprintf("throwaway%d.example", i))
+	}
+	// Using example.com again makes throwaway0 the least recently used.
+	check("example.com")
+	check("one-more.example")
+
+	calls := resolver.calls
+	check("example.com")
+	if resolver.calls != calls {
+		t.Fatal("a recently used domain was evicted by a full cache")
+	}
+	check("throwaway1.example")
+	if resolver.calls != calls {
+		t.Fatal("more than one entry was evicted for one insert")
+	}
+	check("throwaway0.example")
+	if resolver.calls != calls+1 {
+		t.Fatal("the least recently used domain was not evicted")
+	}
+}
+
+// Requirement: EH-F-04
+func TestHasMXRecord_FailsOpen(t *testing.T) {
+	previous := defaultMXChecker
+	t.Cleanup(func() { defaultMXChecker = previous })
+
+	resolver := newFakeMXResolver()
+	defaultMXChecker = NewMXChecker(resolver, time.Second, time.Hour)
+
+	if !HasMXRecord("example.com") {
+		t.Fatal("HasMXRecord(example.com) = false, want true")
+	}
+	if HasMXRecord("no-mx.example") {
+		t.Fatal("HasMXRecord(no-mx.example) = true, want false")
+	}
+
+	resolver.err = errors.New("resolver unreachable")
+	if !HasMXRecord("unresolvable.example") {
+		t.Fatal("HasMXRecord() = false on a DNS failure, want true (fail open)")
+	}
+}
@@ -11,10 +11,10 @@
 // Two kinds of checks are performed, at two different points:
 //
 //   - Decoding-time checks (DecodeRegisterRequest, DecodeLoginRequest):
-//     payload size within a defined limit and no unexpected additional
-//     JSON fields. These are properties of the raw request body, so they
-//     are enforced while decoding it, before a RegisterRequest/LoginRequest
-//     value even exists.
+//     payload size within a defined limit, no pathologically deep nesting
+//     (ErrJSONTooDeep), and no unexpected additional JSON fields. These
+//     are properties of the raw request body, so they are enforced while
+//     decoding it, before a RegisterRequest/LoginRequest value even exists.
 //   - Field-level checks (ValidateRegister, ValidateLogin): presence and
 //     shape of each field once decoded - email format (RFC 5322), password
 //     length and character-class complexity, and SSH public key
@@ -54,6 +54,14 @@ import (
 // be a resource-exhaustion attempt rather than a legitimate request.
 const maxPayloadBytes = 2048
 
+// maxJSONDepth bounds how deeply objects and arrays may nest in a request
+// body. Every request this package decodes is one flat object (depth 1);
+// the limit leaves room for that and nothing resembling a nesting attack.
+// maxPayloadBytes already caps the damage at about a thousand levels, but
+// rejecting before encoding/json recurses into them costs nothing and
+// gives the failure its own name in the logs.
+const maxJSONDepth = 4
+
 // Sentinel errors returned by DecodeRegisterRequest, DecodeLoginRequest,
 // ValidateRegister, and ValidateLogin. Callers can match on these with
 // errors.Is; none of them include the offending field's value, so a caller
@@ -63,8 +71,10 @@ var (
 	ErrPayloadTooLarge      = errors.New("request payload exceeds the size limit")
 	ErrUnknownField         = errors.New("request payload contains an unexpected field")
 	ErrMalformedJSON        = errors.New("request payload is not valid JSON")
+	ErrJSONTooDeep          = errors.New("request payload is nested too deeply")
 	ErrEmailRequired        = errors.New("email is required")
 	ErrEmailInvalid         = errors.New("email is invalid")
+	ErrEmailTooLong         = errors.New("email is too long")
 	ErrPasswordRequired     = errors.New("password is required")
 	ErrPasswordTooShort     = errors.New("password is too short")
 	ErrPasswordTooLong      = errors.New("password is too long")
@@ -73,6 +83,16 @@ var (
 	ErrSSHPublicKeyInvalid  = errors.New("ssh public key is invalid")
 )
 
+// maxEmailLength and maxEmailLocalPartLength are RFC 5321's limits on a
+// deliverable address (§4.5.3.1: 64 octets of local part, and a 256-octet
+// path whose angle brackets leave 254 for the address). mail.ParseAddress
+// does not enforce either, and an address past them could never receive
+// mail anyway. Lengths are in bytes, as RFC 5321 counts octets.
+const (
+	maxEmailLength          = 254
+	maxEmailLocalPartLength = 64
+)
+
 // minPasswordLength, maxPasswordLength, and minPasswordCategories implement
 // the password policy shared by EH-F-04 (register) and EH-F-05 (login).
 const (
@@ -135,6 +155,9 @@ func decodeJSON(r io.Reader, dst any) error {
 	if len(body) > maxPayloadBytes {
 		return ErrPayloadTooLarge
 	}
+	if exceedsJSONDepth(body, maxJSONDepth) {
+		return ErrJSONTooDeep
+	}
 
 	decoder := json.NewDecoder(bytes.NewReader(body))
 	decoder.DisallowUnknownFields()
@@ -172,16 +195,54 @@ func ValidateLogin(req LoginRequest) error {
 	return validatePassword(req.Password)
 }
 
+// exceedsJSONDepth reports whether body opens more than limit nested
+// objects or arrays at any point, ignoring brackets inside strings. It
+// does not otherwise check that body is valid JSON; the decoder does.
+func exceedsJSONDepth(body []byte, limit int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > limit {
+				return true
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return false
+}
+
 // validateEmail checks that email is present and structurally a single
 // RFC 5322 address (e.g. "local@domain"), the minimal shape shared by every
-// valid email address, without enforcing any additional allow/deny policy.
+// valid email address, and that it is within RFC 5321's length limits
+// (ErrEmailTooLong), without enforcing any additional allow/deny policy.
 func validateEmail(email string) error {
 	if strings.TrimSpace(email) == "" {
 		return ErrEmailRequired
 	}
+	if len(email) > maxEmailLength {
+		return ErrEmailTooLong
+	}
 	if _, err := mail.ParseAddress(email); err != nil {
 		return ErrEmailInvalid
 	}
+	if at := strings.LastIndex(email, "@"); at > maxEmailLocalPartLength {
+		return ErrEmailTooLong
+	}
 	return nil
 }
 
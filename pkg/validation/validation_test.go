@@ -22,6 +22,10 @@ const validPassword = "Str0ng!Pass"
 // character categories.
 var password128 = strings.Repeat("Aa1!", 32)
 
+// email254 is exactly 254 bytes long with a 64-byte local part, both
+// RFC 5321 maxima.
+var email254 = strings.Repeat("a", 64) + "@" + strings.Repeat("b", 185) + ".com"
+
 // Requirement: DV-F-02
 func TestValidateRegister(t *testing.T) {
 	tests := []struct {
@@ -56,6 +60,33 @@ func TestValidateRegister(t *testing.T) {
 			},
 			wantErr: validation.ErrEmailInvalid,
 		},
+		{
+			name: "email of exactly 254 characters passes",
+			request: validation.RegisterRequest{
+				Email:        email254,
+				Password:     validPassword,
+				SSHPublicKey: validSSHPublicKey,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "email of 300 characters is rejected as too long",
+			request: validation.RegisterRequest{
+				Email:        strings.Repeat("a", 64) + "@" + strings.Repeat("b", 231) + ".com",
+				Password:     validPassword,
+				SSHPublicKey: validSSHPublicKey,
+			},
+			wantErr: validation.ErrEmailTooLong,
+		},
+		{
+			name: "email with a 65-character local part is rejected as too long",
+			request: validation.RegisterRequest{
+				Email:        strings.Repeat("a", 65) + "@example.com",
+				Password:     validPassword,
+				SSHPublicKey: validSSHPublicKey,
+			},
+			wantErr: validation.ErrEmailTooLong,
+		},
 		{
 			name: "missing password is rejected",
 			request: validation.RegisterRequest{
@@ -180,6 +211,22 @@ func TestValidateLogin(t *testing.T) {
 			},
 			wantErr: validation.ErrEmailInvalid,
 		},
+		{
+			name: "email of exactly 254 characters passes",
+			request: validation.LoginRequest{
+				Email:    email254,
+				Password: validPassword,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "email of 300 characters is rejected as too long",
+			request: validation.LoginRequest{
+				Email:    strings.Repeat("a", 64) + "@" + strings.Repeat("b", 231) + ".com",
+				Password: validPassword,
+			},
+			wantErr: validation.ErrEmailTooLong,
+		},
 		{
 			name: "missing password is rejected",
 			request: validation.LoginRequest{
@@ -265,6 +312,29 @@ func TestDecodeRegisterRequest(t *testing.T) {
 			body:    `{"email":"user@example.com","password":"` + validPassword + `","ssh_public_key":"` + strings.Repeat("a", 2000) + `"}`,
 			wantErr: validation.ErrPayloadTooLarge,
 		},
+		{
+			name:    "deeply nested payload is rejected",
+			body:    `{"email":` + strings.Repeat(`{"a":`, 300) + `1` + strings.Repeat("}", 300) + `}`,
+			wantErr: validation.ErrJSONTooDeep,
+		},
+		{
+			name: "brackets inside a string do not count as nesting",
+			body: `{"email":"user@example.com","password":"[[[[[{{{{{","ssh_public_key":"` + validSSHPublicKey + `"}`,
+			want: validation.RegisterRequest{
+				Email:        "user@example.com",
+				Password:     "[[[[[{{{{{",
+				SSHPublicKey: validSSHPublicKey,
+			},
+		},
+		{
+			name: "an escaped quote does not end a string early",
+			body: `{"email":"user@example.com","password":"\"[[[[[","ssh_public_key":"` + validSSHPublicKey + `"}`,
+			want: validation.RegisterRequest{
+				Email:        "user@example.com",
+				Password:     `"[[[[[`,
+				SSHPublicKey: validSSHPublicKey,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -312,6 +382,11 @@ func TestDecodeLoginRequest(t *testing.T) {
 			body:    `{"email":"user@example.com","password":"` + strings.Repeat("a", 2010) + `"}`,
 			wantErr: validation.ErrPayloadTooLarge,
 		},
+		{
+			name:    "deeply nested payload is rejected",
+			body:    `{"email":"user@example.com","password":` + strings.Repeat("[", 500) + strings.Repeat("]", 500) + `}`,
+			wantErr: validation.ErrJSONTooDeep,
+		},
 	}
 
 	for _, tt := range tests {
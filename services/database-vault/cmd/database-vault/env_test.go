@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Requirement: RD-04
+func TestGetEnvDuration(t *testing.T) {
+	const name = "RAM_USB_DATABASE_VAULT_TEST_DURATION"
+	const fallback = time.Minute
+
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: fallback},
+		{value: "90s", want: 90 * time.Second},
+		{value: "0s", wantErr: true},
+		{value: "-5s", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(name, tt.value)
+
+			got, err := getEnvDuration(name, fallback)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("getEnvDuration() error = nil, want non-nil for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getEnvDuration() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("getEnvDuration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
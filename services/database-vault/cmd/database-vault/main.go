@@ -78,6 +78,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -85,10 +86,13 @@ import (
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
+	"github.com/Verryx-02/RAM-USB/pkg/httpmethod"
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/pkg/metrics"
 	"github.com/Verryx-02/RAM-USB/pkg/mtls"
+	"github.com/Verryx-02/RAM-USB/pkg/pgpool"
 	"github.com/Verryx-02/RAM-USB/pkg/pki"
+	"github.com/Verryx-02/RAM-USB/pkg/secrets"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/encryption"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/httpapi"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/login"
@@ -124,9 +128,25 @@ const (
 	envPublicKeyListenAddr = "RAM_USB_DATABASE_VAULT_PUBLIC_KEY_LISTEN_ADDR"
 
 	// envDatabaseURL is the Postgres connection string pgxpool.New
-	// parses (DV-F-08).
+	// parses (DV-F-08). It carries the database password, so it may
+	// instead be supplied as a file (see requireSecretEnv).
+	// Its pool_min_conns parameter (default 0, meaning one) sets how many
+	// connections pgpool.Warm opens at startup.
+	// Its sslmode must be require or stricter unless
+	// envDatabaseAllowPlaintext is set (pgpool.RequireTLS).
 	envDatabaseURL = "RAM_USB_DATABASE_VAULT_DATABASE_URL"
 
+	// envDatabaseAllowPlaintext, set to true, skips pgpool.RequireTLS's
+	// check on envDatabaseURL, for a development database reached over a
+	// private container network without TLS. Optional: defaults to false;
+	// a value that is not a bool fails startup (RD-04).
+	envDatabaseAllowPlaintext = "RAM_USB_DATABASE_VAULT_DATABASE_ALLOW_PLAINTEXT"
+
+	// envDatabasePoolStatsInterval is how often pgpool.MonitorStats logs
+	// the database pool's connection counts and checks them for a leak,
+	// as a Go duration string. Optional: unset or "0s" disables it.
+	envDatabasePoolStatsInterval = "RAM_USB_DATABASE_VAULT_DATABASE_POOL_STATS_INTERVAL"
+
 	// envMigrationsDir locates the directory of SQL migration files
 	// (internal/schema.Apply) applied once at startup, before this
 	// process starts accepting connections. Optional: defaults to
@@ -138,6 +158,14 @@ const (
 	// pattern as every other unestablished env var name in this file.
 	envMigrationsDir = "RAM_USB_DATABASE_VAULT_MIGRATIONS_DIR"
 
+	// envDatabaseStartupTimeout bounds how long startup keeps retrying
+	// (internal/schema.NewWithRetry) a database that is not yet accepting
+	// connections, e.g. because its container is still starting, as a Go
+	// duration string. Optional: defaults to
+	// defaultDatabaseStartupTimeout, and must be positive. Once spent,
+	// startup fails (RD-04).
+	envDatabaseStartupTimeout = "RAM_USB_DATABASE_VAULT_DATABASE_STARTUP_TIMEOUT"
+
 	// envStorageServiceURL is Storage-Service's base URL (DV-F-09), e.g.
 	// "https://storage-service.internal:8443".
 	envStorageServiceURL = "RAM_USB_STORAGE_SERVICE_URL"
@@ -150,8 +178,60 @@ const (
 	// both inbound listeners and the outbound Storage-Service client (see
 	// this file's package doc comment).
 	envMQTTBrokerURL = "RAM_USB_MQTT_BROKER_URL"
+
+	// envMaxConcurrentHashes caps how many Argon2id computations
+	// (DV-F-07/DV-F-14) run at once (password.Limiter), as a positive
+	// integer. Optional: defaults to defaultMaxConcurrentHashes.
+	envMaxConcurrentHashes = "RAM_USB_DATABASE_VAULT_MAX_CONCURRENT_HASHES"
+
+	// envHashQueueTimeout is how long a register/login request waits for
+	// a free hashing slot before being answered HTTP 503, as a Go
+	// duration string ("0s" rejects immediately instead of queuing).
+	// Optional: defaults to defaultHashQueueTimeout.
+	envHashQueueTimeout = "RAM_USB_DATABASE_VAULT_HASH_QUEUE_TIMEOUT"
+
+	// envRegisterMinDuration is the least time a validated registration
+	// takes to answer (httpapi.Handler.RegisterMinDuration), as a Go
+	// duration string, so a 409 for an existing email is not measurably
+	// faster than a successful registration. Optional: unset or "0s"
+	// disables padding. It should exceed a typical successful
+	// registration's latency to be effective.
+	envRegisterMinDuration = "RAM_USB_DATABASE_VAULT_REGISTER_MIN_DURATION"
+
+	// envMaintenanceFile names a file whose existence puts this server in
+	// maintenance mode (httpapi.FileMaintenance): registrations are
+	// answered HTTP 503 while logins and public-key lookups keep serving.
+	// Optional: unset means maintenance mode cannot be entered.
+	envMaintenanceFile = "RAM_USB_DATABASE_VAULT_MAINTENANCE_FILE"
+
+	// envSSHKeyBlocklistFile names a file of SSH key fingerprints, one
+	// "SHA256:..." per line, that registration refuses
+	// (httpapi.LoadSSHKeyBlocklist). Optional: unset blocks no key. A file
+	// that is set but unreadable or malformed fails startup (RD-04).
+	envSSHKeyBlocklistFile = "RAM_USB_DATABASE_VAULT_SSH_KEY_BLOCKLIST_FILE"
+
+	// envSSHKeyComments is what registration does with a submitted SSH
+	// public key's comment: "keep" it, "strip" it before storage, or
+	// "reject" the registration (httpapi.SSHKeyCommentPolicy). Optional:
+	// unset keeps it. Any other value fails startup (RD-04).
+	envSSHKeyComments = "RAM_USB_DATABASE_VAULT_SSH_KEY_COMMENTS"
+
+	// envEncryptionSelfTestInterval is how often encryption.SelfTest
+	// re-checks the master key, as a Go duration string. While a check
+	// fails, registrations are answered HTTP 503 and an error carrying
+	// "alert"=encryption.SelfTestAlertKey is logged. Optional, defaulting
+	// to defaultEncryptionSelfTestInterval; "0s" disables the self-test.
+	envEncryptionSelfTestInterval = "RAM_USB_DATABASE_VAULT_ENCRYPTION_SELF_TEST_INTERVAL"
 )
 
+// defaultMaxConcurrentHashes bounds peak Argon2id working memory at
+// 8 * 46 MiB (about 368 MiB) by default.
+const defaultMaxConcurrentHashes = 8
+
+// defaultHashQueueTimeout lets a short burst queue rather than fail, while
+// still answering well inside Security-Switch's own call deadline.
+const defaultHashQueueTimeout = 5 * time.Second
+
 // organizationStorageService is the Subject.Organization DV-F-09 requires
 // of Storage-Service's server certificate. posix.CreatePOSIXUser's doc
 // comment already documents this literal string; it is not exported by
@@ -182,11 +262,28 @@ const metricsPublishInterval = time.Minute
 // broker's connection handshake at startup.
 const connectTimeout = 10 * time.Second
 
+// defaultDatabaseStartupTimeout is envDatabaseStartupTimeout's fallback:
+// long enough for a freshly started Postgres container to finish
+// initializing.
+const defaultDatabaseStartupTimeout = 60 * time.Second
+
+// poolLeakSamples is how many consecutive pgpool.MonitorStats samples
+// with no idle connection make a suspected leak: at a one-minute
+// interval, five minutes of a fully acquired pool.
+const poolLeakSamples = 5
+
+// defaultEncryptionSelfTestInterval is envEncryptionSelfTestInterval's
+// fallback. A check is one AES-GCM decryption, so running it every minute
+// costs nothing measurable.
+const defaultEncryptionSelfTestInterval = time.Minute
+
 // defaultMigrationsDir is envMigrationsDir's fallback: the migrations
 // directory's checked-in location relative to this repository's root.
 const defaultMigrationsDir = "services/database-vault/migrations"
 
 func main() {
+	slog.SetDefault(logging.NewLogger(os.Stderr))
+
 	if err := run(); err != nil {
 		slog.Error("database-vault: fatal startup error", "error", logging.Sanitize(err.Error()))
 		os.Exit(1)
@@ -228,13 +325,27 @@ func run() error {
 		return fmt.Errorf("build server tls config: %w", err)
 	}
 
-	databaseURL, err := requireEnv(envDatabaseURL)
+	databaseURL, err := requireSecretEnv(envDatabaseURL)
+	if err != nil {
+		return err
+	}
+	allowPlaintext, err := getEnvBool(envDatabaseAllowPlaintext)
+	if err != nil {
+		return err
+	}
+	if allowPlaintext {
+		slog.Warn("database-vault: database connection may be unencrypted, " + envDatabaseAllowPlaintext + " is set")
+	} else if err := pgpool.RequireTLS(databaseURL); err != nil {
+		return fmt.Errorf("%s: %w", envDatabaseURL, err)
+	}
+
+	startupTimeout, err := getEnvDuration(envDatabaseStartupTimeout, defaultDatabaseStartupTimeout)
 	if err != nil {
 		return err
 	}
 
 	migrationsDir := getEnvOrDefault(envMigrationsDir, defaultMigrationsDir)
-	migration, err := schema.New(databaseURL, migrationsDir)
+	migration, err := schema.NewWithRetry(ctx, databaseURL, migrationsDir, startupTimeout)
 	if err != nil {
 		return fmt.Errorf("build schema migration: %w", err)
 	}
@@ -253,20 +364,84 @@ func run() error {
 	}
 	defer pool.Close()
 
+	// Open the pool's pool_min_conns connections now rather than on the
+	// first requests (pkg/pgpool's doc comment).
+	if err := pgpool.Warm(ctx, pool); err != nil {
+		return fmt.Errorf("warm database pool: %w", err)
+	}
+
+	var poolStatsInterval time.Duration
+	if value := getEnvOrDefault(envDatabasePoolStatsInterval, ""); value != "" {
+		poolStatsInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("environment variable %s is not a valid duration: %w", envDatabasePoolStatsInterval, err)
+		}
+	}
+	if poolStatsInterval < 0 {
+		return fmt.Errorf("%s must not be negative, got %s", envDatabasePoolStatsInterval, poolStatsInterval)
+	}
+	// leakDetector stays nil, never suspecting a leak, unless the stats
+	// monitor runs to feed it.
+	var leakDetector *pgpool.LeakDetector
+	if poolStatsInterval > 0 {
+		leakDetector = &pgpool.LeakDetector{Samples: poolLeakSamples}
+		go pgpool.MonitorStats(ctx, pool, poolStatsInterval, leakDetector)
+	}
+
 	storageServiceClient, storageServiceURL, err := buildStorageServiceClient(serverTLSConfig)
 	if err != nil {
 		return fmt.Errorf("build storage-service client: %w", err)
 	}
 
+	hashLimiter, err := buildHashLimiter()
+	if err != nil {
+		return fmt.Errorf("build password hash limiter: %w", err)
+	}
+
+	var registerMinDuration time.Duration
+	if value := getEnvOrDefault(envRegisterMinDuration, ""); value != "" {
+		registerMinDuration, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("environment variable %s is not a valid duration: %w", envRegisterMinDuration, err)
+		}
+		if registerMinDuration < 0 {
+			return fmt.Errorf("environment variable %s must not be negative, got %s", envRegisterMinDuration, registerMinDuration)
+		}
+	}
+
+	encryptionHealth, err := startEncryptionSelfTest(ctx, masterKey)
+	if err != nil {
+		return err
+	}
+
+	var sshKeyBlocklist httpapi.SSHKeyBlocklist
+	if path := getEnvOrDefault(envSSHKeyBlocklistFile, ""); path != "" {
+		sshKeyBlocklist, err = httpapi.LoadSSHKeyBlocklist(path)
+		if err != nil {
+			return fmt.Errorf("load %s: %w", envSSHKeyBlocklistFile, err)
+		}
+	}
+
+	sshKeyComments, err := httpapi.ParseSSHKeyCommentPolicy(getEnvOrDefault(envSSHKeyComments, "keep"))
+	if err != nil {
+		return fmt.Errorf("%s: %w", envSSHKeyComments, err)
+	}
+
 	counters := &httpapi.Counters{}
 
 	handler := &httpapi.Handler{
-		Store:            registration.StorageAdapter{DB: storage.PoolBeginner{Pool: pool}},
-		POSIXProvisioner: registration.POSIXAdapter{Client: storageServiceClient, BaseURL: storageServiceURL},
-		LoginStore:       login.StorageAdapter{DB: storage.PoolQuerier{Pool: pool}},
-		MasterKey:        masterKey,
-		Pepper:           pepper,
-		Metrics:          counters,
+		Store:               registration.StorageAdapter{DB: storage.PoolBeginner{Pool: pool}},
+		POSIXProvisioner:    registration.POSIXAdapter{Client: storageServiceClient, BaseURL: storageServiceURL},
+		LoginStore:          login.StorageAdapter{DB: storage.PoolQuerier{Pool: pool}, Writer: storage.PoolExecer{Pool: pool}},
+		MasterKey:           masterKey,
+		Pepper:              pepper,
+		HashLimiter:         hashLimiter,
+		RegisterMinDuration: registerMinDuration,
+		Maintenance:         httpapi.FileMaintenance{Path: getEnvOrDefault(envMaintenanceFile, "")},
+		EncryptionHealth:    encryptionHealth,
+		SSHKeyBlocklist:     sshKeyBlocklist,
+		SSHKeyComments:      sshKeyComments,
+		Metrics:             counters,
 	}
 
 	// publicKeyHandler shares the same counters as handler (DV-F-16/
@@ -279,8 +454,8 @@ func run() error {
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc(httpapi.RegisterPath, handler.Register)
-	mux.HandleFunc(httpapi.LoginPath, handler.Login)
+	mux.HandleFunc(httpapi.RegisterPath, httpmethod.Allow(handler.Register, http.MethodPost))
+	mux.HandleFunc(httpapi.LoginPath, httpmethod.Allow(handler.Login, http.MethodPost))
 
 	httpServer := &http.Server{
 		Addr: listenAddr,
@@ -301,7 +476,8 @@ func run() error {
 	// internal/server/pubkey_server.go for why these are two listeners,
 	// not one.
 	publicKeyMux := http.NewServeMux()
-	publicKeyMux.HandleFunc(httpapi.PublicKeyPath, publicKeyHandler.PublicKey)
+	publicKeyMux.HandleFunc(httpapi.PublicKeyPath, httpmethod.Allow(publicKeyHandler.PublicKey, http.MethodGet, http.MethodHead))
+	publicKeyMux.HandleFunc(httpapi.VerifySSHKeyPath, httpmethod.Allow(publicKeyHandler.VerifySSHKey, http.MethodPost))
 
 	publicKeyHTTPServer := &http.Server{
 		Addr:              publicKeyListenAddr,
@@ -316,9 +492,16 @@ func run() error {
 	}
 	if metricsClient != nil {
 		defer metricsClient.Disconnect(250)
-		go metrics.Run(ctx, metricsPublishInterval, func(publishCtx context.Context) error {
-			return metrics.PublishOnce(publishCtx, metricsClient, serviceName, counters.Snapshot())
-		})
+		publishAlert, err := metrics.LoadFailureAlert()
+		if err != nil {
+			return fmt.Errorf("build metrics publish alert: %w", err)
+		}
+		go metrics.Run(ctx, metricsPublishInterval, publishAlert.Wrap(func(publishCtx context.Context) error {
+			snapshot := counters.Snapshot()
+			pgpool.StatsOf(pool).Record(&snapshot)
+			leakDetector.Record(&snapshot)
+			return metrics.PublishOnce(publishCtx, metricsClient, serviceName, snapshot)
+		}))
 	}
 
 	serveErr := make(chan error, 1)
@@ -376,12 +559,46 @@ func requireEnv(name string) (string, error) {
 	return value, nil
 }
 
+// requireSecretEnv is requireEnv for a value carrying a credential: name
+// may instead be supplied as a file whose path is in name+"_FILE"
+// (pkg/secrets), which takes precedence, so the credential need not sit
+// in the process environment at all.
+func requireSecretEnv(name string) (string, error) {
+	value, ok, err := secrets.Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("required environment variable %s (or %s%s) is not set", name, name, secrets.FileSuffix)
+	}
+	return value, nil
+}
+
+// getEnvBool reads name from the environment as a bool, defaulting to
+// false if unset or empty. A value present but not parseable as a bool
+// (strconv.ParseBool's accepted forms) is a startup failure (RD-04,
+// fail-secure) - not silently treated as false.
+func getEnvBool(name string) (bool, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("environment variable %s is not a valid bool: %w", name, err)
+	}
+	return parsed, nil
+}
+
 // getEnvOrDefault reads name from the environment, returning fallback if it
 // is unset or empty. Unlike requireEnv, an unset value here is not a
-// startup failure - only envMigrationsDir uses this, since it has a
-// sensible checked-in-path default (see defaultMigrationsDir), unlike
-// every other value in this file, which has no safe default and so must
-// come from requireEnv.
+// startup failure - only envMigrationsDir (a sensible checked-in-path
+// default, see defaultMigrationsDir), envDatabaseStartupTimeout,
+// envRegisterMinDuration, envMaintenanceFile, envSSHKeyBlocklistFile,
+// envSSHKeyComments, envEncryptionSelfTestInterval,
+// envDatabasePoolStatsInterval, and buildHashLimiter's tuning values use
+// this, unlike every other value in this file, which has no safe default
+// and so must come from requireEnv.
 func getEnvOrDefault(name, fallback string) string {
 	value, ok := os.LookupEnv(name)
 	if !ok || value == "" {
@@ -390,6 +607,79 @@ func getEnvOrDefault(name, fallback string) string {
 	return value
 }
 
+// getEnvDuration reads name from the environment as a Go duration string
+// (time.ParseDuration), returning fallback if it is unset or empty. A
+// value present but unparseable, zero, or negative is a startup failure
+// (RD-04). Only envDatabaseStartupTimeout uses it, since a timeout of
+// zero or less makes schema.NewWithRetry give up after one attempt; the
+// other durations in this file give "0s" a meaning of their own.
+func getEnvDuration(name string, fallback time.Duration) (time.Duration, error) {
+	value := getEnvOrDefault(name, "")
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s is not a valid duration: %w", name, err)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("environment variable %s must be positive, got %s", name, parsed)
+	}
+	return parsed, nil
+}
+
+// buildHashLimiter reads envMaxConcurrentHashes/envHashQueueTimeout into a
+// password.Limiter. A value present but unparseable is a startup failure
+// (RD-04, fail-secure) - not silently replaced by its default.
+func buildHashLimiter() (*password.Limiter, error) {
+	maxConcurrent := defaultMaxConcurrentHashes
+	if value := getEnvOrDefault(envMaxConcurrentHashes, ""); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable %s is not a valid integer: %w", envMaxConcurrentHashes, err)
+		}
+		maxConcurrent = parsed
+	}
+
+	queueTimeout := defaultHashQueueTimeout
+	if value := getEnvOrDefault(envHashQueueTimeout, ""); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable %s is not a valid duration: %w", envHashQueueTimeout, err)
+		}
+		queueTimeout = parsed
+	}
+
+	return password.NewLimiter(maxConcurrent, queueTimeout)
+}
+
+// startEncryptionSelfTest builds an encryption.SelfTest over masterKey and
+// runs it every envEncryptionSelfTestInterval until ctx is done. It
+// returns nil (no health gate) if the interval is "0s".
+func startEncryptionSelfTest(ctx context.Context, masterKey []byte) (httpapi.EncryptionHealth, error) {
+	interval := defaultEncryptionSelfTestInterval
+	if value := getEnvOrDefault(envEncryptionSelfTestInterval, ""); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable %s is not a valid duration: %w", envEncryptionSelfTestInterval, err)
+		}
+		if parsed < 0 {
+			return nil, fmt.Errorf("environment variable %s must not be negative, got %s", envEncryptionSelfTestInterval, parsed)
+		}
+		interval = parsed
+	}
+	if interval == 0 {
+		return nil, nil
+	}
+
+	selfTest, err := encryption.NewSelfTest(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	go selfTest.Run(ctx, interval)
+	return selfTest, nil
+}
+
 // buildServerTLSConfig bootstraps this server's one TLS identity from the
 // Certificate-Authority (CA-F-04, PKI-F-01), using pki.LoadBootstrapToken's
 // single-use token exactly once. The returned *tls.Config is shared by
@@ -12,6 +12,7 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 
@@ -29,12 +30,24 @@ const (
 	// aes.NewCipher (16/24/32-byte keys select AES-128/192/256).
 	derivedKeySize = 32
 
+	// gcmTagSize is cipher.NewGCM's default authentication tag length,
+	// which Seal appends to every ciphertext EncryptEmail returns.
+	gcmTagSize = 16
+
 	// hkdfInfo domain-separates this derived key from any other key a
 	// future caller might ever derive from the same master key via
 	// HKDF, per RFC 5869's "info" parameter guidance.
 	hkdfInfo = "RAM-USB/database-vault/email-encryption"
 )
 
+// ErrCorruptedEncryptedEmail means an EncryptedEmail handed to DecryptEmail
+// does not have the shape EncryptEmail always produces (a 16-byte salt, a
+// 12-byte nonce, and a ciphertext at least as long as the GCM tag) — a
+// truncated or partially-written email_encrypted value, not a wrong key or
+// tampered ciphertext. Checked up front because cipher.AEAD.Open panics,
+// rather than returning an error, on a nonce of the wrong length.
+var ErrCorruptedEncryptedEmail = errors.New("encryption: encrypted email is corrupted")
+
 // EncryptedEmail holds everything DV-F-08 needs to persist and
 // DecryptEmail needs to reverse an EncryptEmail call: the random
 // per-record salt, the random GCM nonce, and the resulting ciphertext.
@@ -90,7 +103,15 @@ func EncryptEmail(masterKey []byte, email logging.Redacted) (EncryptedEmail, err
 // original plaintext is the only way to confirm EncryptEmail is correct.
 // No SRS requirement yet describes a production flow that reads the
 // email back; see this task's report for that gap.
+//
+// A returned error wrapping ErrCorruptedEncryptedEmail (checked via
+// errors.Is) means enc itself is structurally invalid; any other error
+// means decryption was attempted and failed authentication.
 func DecryptEmail(masterKey []byte, enc EncryptedEmail) (string, error) {
+	if err := validateShape(enc); err != nil {
+		return "", err
+	}
+
 	gcm, err := newGCM(masterKey, enc.Salt)
 	if err != nil {
 		return "", err
@@ -104,6 +125,22 @@ func DecryptEmail(masterKey []byte, enc EncryptedEmail) (string, error) {
 	return string(plaintext), nil
 }
 
+// validateShape rejects an EncryptedEmail whose salt, nonce, or ciphertext
+// length could not have come from EncryptEmail, before any key derivation
+// or GCM call sees it.
+func validateShape(enc EncryptedEmail) error {
+	if len(enc.Salt) != saltSize {
+		return fmt.Errorf("%w: salt is %d bytes, want %d", ErrCorruptedEncryptedEmail, len(enc.Salt), saltSize)
+	}
+	if len(enc.Nonce) != nonceSize {
+		return fmt.Errorf("%w: nonce is %d bytes, want %d", ErrCorruptedEncryptedEmail, len(enc.Nonce), nonceSize)
+	}
+	if len(enc.Ciphertext) < gcmTagSize {
+		return fmt.Errorf("%w: ciphertext is %d bytes, shorter than the %d-byte GCM tag", ErrCorruptedEncryptedEmail, len(enc.Ciphertext), gcmTagSize)
+	}
+	return nil
+}
+
 // newGCM derives a per-record AES-256 key from masterKey and salt via
 // HKDF-SHA256 (DV-F-04), and wraps it in an AES-256-GCM cipher.AEAD. The
 // derived key is zeroed as soon as the AES cipher block has consumed it
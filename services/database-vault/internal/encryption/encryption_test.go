@@ -2,6 +2,7 @@ package encryption
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
@@ -118,3 +119,30 @@ func TestDecryptEmail_WrongMasterKeyFails(t *testing.T) {
 		t.Error("DecryptEmail succeeded with wrong master key, want failure")
 	}
 }
+
+// Requirement: DV-F-04
+func TestDecryptEmail_CorruptedShapeFails(t *testing.T) {
+	enc, err := EncryptEmail(testMasterKey, logging.Redacted("corrupt@example.com"))
+	if err != nil {
+		t.Fatalf("EncryptEmail returned error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		enc  EncryptedEmail
+	}{
+		{name: "empty ciphertext", enc: EncryptedEmail{Salt: enc.Salt, Nonce: enc.Nonce, Ciphertext: nil}},
+		{name: "truncated salt", enc: EncryptedEmail{Salt: enc.Salt[:8], Nonce: enc.Nonce, Ciphertext: enc.Ciphertext}},
+		{name: "empty salt", enc: EncryptedEmail{Salt: nil, Nonce: enc.Nonce, Ciphertext: enc.Ciphertext}},
+		{name: "truncated nonce", enc: EncryptedEmail{Salt: enc.Salt, Nonce: enc.Nonce[:4], Ciphertext: enc.Ciphertext}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecryptEmail(testMasterKey, tt.enc)
+			if !errors.Is(err, ErrCorruptedEncryptedEmail) {
+				t.Fatalf("DecryptEmail() error = %v, want wrapping ErrCorruptedEncryptedEmail", err)
+			}
+		})
+	}
+}
@@ -4,20 +4,24 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"os"
+
+	"github.com/Verryx-02/RAM-USB/pkg/secrets"
 )
 
 // masterKeyEnvVar is the configurable source DV-F-05 requires. Per SRS
 // §2.6 ("Assumptions and dependencies"), the master key is assumed to
-// reside in an environment variable for now; no other source (file, KMS,
-// secrets manager) is in scope until the SRS says otherwise.
+// reside in an environment variable, either inline or, via
+// masterKeyEnvVar+"_FILE", in a mounted secret file (pkg/secrets); no
+// other source (KMS, secrets manager) is in scope until the SRS says
+// otherwise.
 const masterKeyEnvVar = "RAM_USB_MASTER_KEY"
 
 // masterKeySize is the length DV-F-05 requires of the decoded master key:
 // 32 bytes, so it selects AES-256 in aes.NewCipher via newGCM.
 const masterKeySize = 32
 
-// ErrMasterKeyMissing means masterKeyEnvVar is unset or empty.
+// ErrMasterKeyMissing means masterKeyEnvVar (and its file variant) is
+// unset or empty.
 var ErrMasterKeyMissing = errors.New("encryption: master key environment variable is not set")
 
 // ErrMasterKeyInvalidEncoding means masterKeyEnvVar's value is not valid
@@ -41,8 +45,11 @@ var ErrMasterKeyInvalidLength = errors.New("encryption: master key has invalid l
 // instead of silently padding, truncating, or falling back to a default
 // key.
 func LoadMasterKey() ([]byte, error) {
-	encoded, ok := os.LookupEnv(masterKeyEnvVar)
-	if !ok || encoded == "" {
+	encoded, ok, err := secrets.Lookup(masterKeyEnvVar)
+	if err != nil {
+		return nil, fmt.Errorf("encryption: %w", err)
+	}
+	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrMasterKeyMissing, masterKeyEnvVar)
 	}
 
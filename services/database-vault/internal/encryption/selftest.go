@@ -0,0 +1,99 @@
+package encryption
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/logging"
+)
+
+// SelfTestAlertKey is the "alert" attribute value SelfTest's failure log
+// line carries, so log-based alerting can match on it without parsing
+// the message, same convention as pkg/metrics.FailureAlertKey.
+const SelfTestAlertKey = "encryption_self_test_failing"
+
+// selfTestCanary is the plaintext SelfTest encrypts once and then keeps
+// decrypting. It is not an email and not a secret.
+const selfTestCanary = "self-test@ram-usb.invalid"
+
+// ErrSelfTestMismatch means the canary decrypted without error but not to
+// the value it was encrypted from.
+var ErrSelfTestMismatch = errors.New("encryption: self-test canary decrypted to the wrong value")
+
+// SelfTest detects the master key changing under a running process (a
+// memory fault, or a bug writing through a shared slice). It encrypts a
+// canary once, at construction, and Check later decrypts that same
+// ciphertext: a fresh encrypt-then-decrypt round trip would pass with a
+// corrupted key too, since both halves would use the same wrong bytes.
+// Once Check fails, Healthy reports false until a later Check passes, and
+// DV-F-04's encryption must not be trusted for new records meanwhile.
+type SelfTest struct {
+	masterKey []byte
+	canary    EncryptedEmail
+	unhealthy atomic.Bool
+}
+
+// NewSelfTest encrypts the canary under masterKey, which must be the same
+// slice EncryptEmail is called with - a copy would not see it change.
+func NewSelfTest(masterKey []byte) (*SelfTest, error) {
+	canary, err := EncryptEmail(masterKey, logging.Redacted(selfTestCanary))
+	if err != nil {
+		return nil, fmt.Errorf("encryption: encrypt self-test canary: %w", err)
+	}
+	return &SelfTest{masterKey: masterKey, canary: canary}, nil
+}
+
+// Check decrypts the canary with the current master key and records the
+// result for Healthy.
+func (s *SelfTest) Check() error {
+	err := s.check()
+	s.unhealthy.Store(err != nil)
+	return err
+}
+
+func (s *SelfTest) check() error {
+	plaintext, err := DecryptEmail(s.masterKey, s.canary)
+	if err != nil {
+		return fmt.Errorf("encryption: decrypt self-test canary: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(plaintext), []byte(selfTestCanary)) != 1 {
+		return ErrSelfTestMismatch
+	}
+	return nil
+}
+
+// Healthy reports whether the most recent Check passed. It is true before
+// the first Check: the canary was just produced by a working key.
+func (s *SelfTest) Healthy() bool {
+	return !s.unhealthy.Load()
+}
+
+// Run calls Check every interval until ctx is done, logging an error
+// (with "alert"=SelfTestAlertKey) when the self-test starts failing and
+// an info line when it recovers.
+func (s *SelfTest) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			wasHealthy := s.Healthy()
+			err := s.Check()
+			switch {
+			case err != nil && wasHealthy:
+				slog.Error("encryption: self-test failing, rejecting registrations",
+					"alert", SelfTestAlertKey, "error", err)
+			case err == nil && !wasHealthy:
+				slog.Info("encryption: self-test passing again")
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// Requirement: DV-F-04
+func TestSelfTest_DetectsMasterKeyCorruption(t *testing.T) {
+	masterKey := bytes.Repeat([]byte{0x01}, 32)
+	selfTest, err := NewSelfTest(masterKey)
+	if err != nil {
+		t.Fatalf("NewSelfTest() error = %v", err)
+	}
+
+	if !selfTest.Healthy() {
+		t.Fatal("Healthy() = false before any Check")
+	}
+	if err := selfTest.Check(); err != nil {
+		t.Fatalf("Check() error = %v with an intact key", err)
+	}
+
+	masterKey[0] ^= 0xff // simulate a flipped byte in memory
+	if err := selfTest.Check(); err == nil {
+		t.Fatal("Check() error = nil after the master key changed")
+	}
+	if selfTest.Healthy() {
+		t.Fatal("Healthy() = true after a failed Check")
+	}
+
+	masterKey[0] ^= 0xff
+	if err := selfTest.Check(); err != nil {
+		t.Fatalf("Check() error = %v after the key was restored", err)
+	}
+	if !selfTest.Healthy() {
+		t.Fatal("Healthy() = false after a passing Check")
+	}
+}
+
+// Requirement: DV-F-04
+func TestSelfTest_RunChecksPeriodically(t *testing.T) {
+	masterKey := bytes.Repeat([]byte{0x01}, 32)
+	selfTest, err := NewSelfTest(masterKey)
+	if err != nil {
+		t.Fatalf("NewSelfTest() error = %v", err)
+	}
+
+	// Corrupted before Run starts, so the test never writes the key while
+	// Run's goroutine reads it.
+	masterKey[31] ^= 0xff
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		selfTest.Run(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for selfTest.Healthy() {
+		if time.Now().After(deadline) {
+			t.Fatal("Run did not mark the self-test unhealthy after the key changed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
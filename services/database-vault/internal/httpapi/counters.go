@@ -18,6 +18,12 @@ type Counters struct {
 	errorCount        atomic.Int64
 	totalResponseMs   atomic.Int64
 	activeConnections atomic.Int64
+	hashesInFlight    atomic.Int64
+	encryptionCount   atomic.Int64
+	// totalEncryptionNs is in nanoseconds, not milliseconds like
+	// totalResponseMs: one encryption takes a few microseconds, so
+	// Duration.Milliseconds would record every one as 0.
+	totalEncryptionNs atomic.Int64
 }
 
 // BeginRequest marks one request as started, incrementing the
@@ -39,6 +45,26 @@ func (c *Counters) EndRequest(duration time.Duration, isError bool) {
 	c.activeConnections.Add(-1)
 }
 
+// BeginHash marks one request as holding a password-hashing slot
+// (Handler.HashLimiter), incrementing the hashes-in-flight gauge. Callers
+// must call EndHash exactly once for every BeginHash call, when they
+// release the slot.
+func (c *Counters) BeginHash() {
+	c.hashesInFlight.Add(1)
+}
+
+// EndHash decrements the hashes-in-flight gauge BeginHash incremented.
+func (c *Counters) EndHash() {
+	c.hashesInFlight.Add(-1)
+}
+
+// RecordEncryption records how long one email encryption took
+// (DV-F-04), for the published average.
+func (c *Counters) RecordEncryption(duration time.Duration) {
+	c.encryptionCount.Add(1)
+	c.totalEncryptionNs.Add(duration.Nanoseconds())
+}
+
 // Snapshot converts the accumulated counts into metrics.Counters
 // (DV-F-16/DV-F-17's payload input) at the moment it's called. It does not
 // reset the accumulated totals — DV-F-16 publishes every minute
@@ -54,10 +80,17 @@ func (c *Counters) Snapshot() metrics.Counters {
 		average = float64(c.totalResponseMs.Load()) / float64(requestCount)
 	}
 
+	var encryptionAverage float64
+	if encryptionCount := c.encryptionCount.Load(); encryptionCount > 0 {
+		encryptionAverage = float64(c.totalEncryptionNs.Load()) / float64(encryptionCount) / float64(time.Millisecond)
+	}
+
 	return metrics.Counters{
-		RequestCount:          requestCount,
-		ErrorCount:            c.errorCount.Load(),
-		AverageResponseTimeMs: average,
-		ActiveConnections:     c.activeConnections.Load(),
+		RequestCount:            requestCount,
+		ErrorCount:              c.errorCount.Load(),
+		AverageResponseTimeMs:   average,
+		ActiveConnections:       c.activeConnections.Load(),
+		HashesInFlight:          c.hashesInFlight.Load(),
+		AverageEncryptionTimeMs: encryptionAverage,
 	}
 }
@@ -60,3 +60,36 @@ func TestCounters_ActiveConnectionsTracksInFlightRequests(t *testing.T) {
 		t.Fatalf("ActiveConnections after one EndRequest = %d, want 1", got)
 	}
 }
+
+// Requirement: DV-F-07
+// Requirement: DV-F-16
+func TestCounters_HashesInFlightTracksHeldSlots(t *testing.T) {
+	c := &Counters{}
+
+	c.BeginHash()
+	c.BeginHash()
+
+	if got := c.Snapshot().HashesInFlight; got != 2 {
+		t.Fatalf("HashesInFlight mid-flight = %d, want 2", got)
+	}
+
+	c.EndHash()
+	c.EndHash()
+
+	if got := c.Snapshot().HashesInFlight; got != 0 {
+		t.Fatalf("HashesInFlight after both EndHash calls = %d, want 0", got)
+	}
+}
+
+// Requirement: DV-F-04
+// Requirement: DV-F-16
+func TestCounters_AverageEncryptionTimeKeepsSubMillisecondDurations(t *testing.T) {
+	c := &Counters{}
+
+	c.RecordEncryption(100 * time.Microsecond)
+	c.RecordEncryption(300 * time.Microsecond)
+
+	if got := c.Snapshot().AverageEncryptionTimeMs; got != 0.2 {
+		t.Fatalf("AverageEncryptionTimeMs = %v, want 0.2", got)
+	}
+}
@@ -19,7 +19,9 @@
 package httpapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
@@ -32,6 +34,7 @@ import (
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/login"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/password"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/registration"
+	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/storage"
 )
 
 // RegisterPath and LoginPath are Database-Vault's own internal endpoint
@@ -68,6 +71,40 @@ type Handler struct {
 	// VerifyPassword use (DV-F-06).
 	Pepper []byte
 
+	// HashLimiter bounds how many Argon2id computations (Register's
+	// HashPassword, Login's VerifyPassword) run at once. A request that
+	// cannot get a slot is answered HTTP 503. If nil, no limit applies.
+	HashLimiter *password.Limiter
+
+	// RegisterMinDuration, if positive, is the least time Register takes
+	// to answer a request that passed validation. A faster outcome -
+	// notably DV-F-12's 409 for an already-registered email, which skips
+	// Storage-Service's POSIX-user creation (DV-F-09) - waits out the
+	// remainder, so whether an email is registered cannot be inferred
+	// from response latency. Zero disables padding.
+	RegisterMinDuration time.Duration
+
+	// Maintenance, if non-nil and active, makes Register answer HTTP 503
+	// (apperrors.NewMaintenance) before reading the request. Login is
+	// unaffected.
+	Maintenance Maintenance
+
+	// EncryptionHealth, if non-nil and not Healthy, makes Register answer
+	// HTTP 503 before reading the request: a master key that fails its
+	// self-test (encryption.SelfTest) must not encrypt new records. Login
+	// never encrypts and is unaffected.
+	EncryptionHealth EncryptionHealth
+
+	// SSHKeyBlocklist, if non-empty, makes Register answer HTTP 400 for a
+	// request whose SSH public key has a listed fingerprint, before any
+	// other work is done. Login is unaffected.
+	SSHKeyBlocklist SSHKeyBlocklist
+
+	// SSHKeyComments decides whether Register stores an SSH public key's
+	// comment, strips it, or rejects the key with HTTP 400 (see
+	// SSHKeyCommentPolicy). The zero value keeps it. Login is unaffected.
+	SSHKeyComments SSHKeyCommentPolicy
+
 	// Metrics accumulates request/error/response-time counts feeding
 	// DV-F-16/DV-F-17's periodic publish. Must not be nil.
 	Metrics *Counters
@@ -102,6 +139,20 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		h.Metrics.EndRequest(time.Since(start), isError)
 	}()
 
+	if h.Maintenance != nil && h.Maintenance.Active() {
+		isError = true
+		h.logger().Warn("register: rejected, maintenance mode is active")
+		writeAppError(w, apperrors.NewMaintenance(errMaintenance))
+		return
+	}
+
+	if h.EncryptionHealth != nil && !h.EncryptionHealth.Healthy() {
+		isError = true
+		h.logger().Error("register: rejected, encryption self-test is failing")
+		writeAppError(w, apperrors.NewServiceUnavailable(errEncryptionUnhealthy))
+		return
+	}
+
 	req, err := validation.DecodeRegisterRequest(r.Body)
 	if err != nil {
 		isError = true
@@ -115,9 +166,28 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.SSHKeyBlocklist.Blocked(req.SSHPublicKey) {
+		isError = true
+		h.failValidation(w, "register", errSSHKeyBlocklisted)
+		return
+	}
+
+	sshPublicKey, err := h.SSHKeyComments.apply(req.SSHPublicKey)
+	if err != nil {
+		isError = true
+		h.failValidation(w, "register", err)
+		return
+	}
+
+	// Validation failures above are not padded: they depend only on the
+	// request itself, never on what is stored.
+	defer h.padRegister(r.Context(), start)
+
 	emailHash := hashing.HashEmail(logging.Redacted(req.Email))
 
+	encryptStart := time.Now()
 	emailEncrypted, err := encryption.EncryptEmail(h.MasterKey, logging.Redacted(req.Email))
+	h.Metrics.RecordEncryption(time.Since(encryptStart))
 	if err != nil {
 		isError = true
 		h.logger().Error("register: encrypt email failed", "error", err)
@@ -133,7 +203,16 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.HashLimiter.Acquire(r.Context()); err != nil {
+		isError = true
+		h.logger().Warn("register: password hashing at capacity", "error", err)
+		writeAppError(w, apperrors.NewServiceUnavailable(err))
+		return
+	}
+	h.Metrics.BeginHash()
 	passwordHash, err := password.HashPassword([]byte(req.Password), salt, h.Pepper)
+	h.Metrics.EndHash()
+	h.HashLimiter.Release()
 	if err != nil {
 		isError = true
 		h.logger().Error("register: hash password failed", "error", err)
@@ -145,7 +224,7 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		EmailHash:      emailHash,
 		EmailEncrypted: emailEncrypted,
 		PasswordHash:   passwordHash,
-		SSHPublicKey:   req.SSHPublicKey,
+		SSHPublicKey:   sshPublicKey,
 	})
 
 	switch result.Outcome {
@@ -156,6 +235,26 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		isError = true
 		h.logger().Warn("register: rejected as duplicate", "error", result.Err)
 		writeAppError(w, apperrors.NewConflict(result.Err))
+	case registration.OutcomeFailed:
+		if errors.Is(result.Err, context.Canceled) {
+			// The caller disconnected mid-request (Security-Switch gave
+			// up or shut down). Nothing is wrong with this service, so it
+			// is neither logged as an error nor counted as one; nobody is
+			// left to read the response.
+			h.logger().Info("register: caller went away", "error", result.Err)
+			writeAppError(w, apperrors.NewServiceUnavailable(result.Err))
+			return
+		}
+		isError = true
+		if errors.Is(result.Err, storage.ErrDatabaseUnavailable) {
+			// Nothing was saved and the request itself is fine, so the
+			// caller may retry: 503, not 500.
+			h.logger().Warn("register: database unavailable", "error", result.Err)
+			writeAppError(w, apperrors.NewServiceUnavailable(result.Err))
+			return
+		}
+		h.logger().Error("register: failed", "error", result.Err)
+		writeAppError(w, apperrors.NewInternal(result.Err))
 	default:
 		isError = true
 		h.logger().Error("register: failed", "error", result.Err)
@@ -163,6 +262,23 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// padRegister sleeps until RegisterMinDuration has passed since start,
+// returning early if ctx is done (the caller has gone, so there is no one
+// left to observe the timing).
+func (h *Handler) padRegister(ctx context.Context, start time.Time) {
+	remaining := h.RegisterMinDuration - time.Since(start)
+	if remaining <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
 // Login handles a login request: decode (DV-F-02), re-validate (DV-F-02),
 // and on success hand off to login.Login (DV-F-13..DV-F-15). On a decode
 // or validation failure, DV-F-20 applies identically to Register.
@@ -187,14 +303,32 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The slot is taken before the lookup, not just around
+	// VerifyPassword inside login.Login: DV-F-15 requires the
+	// nonexistent-email and wrong-password paths to be indistinguishable,
+	// so whether a 503 can happen must not depend on which one this is.
+	if err := h.HashLimiter.Acquire(r.Context()); err != nil {
+		isError = true
+		h.logger().Warn("login: password hashing at capacity", "error", err)
+		writeAppError(w, apperrors.NewServiceUnavailable(err))
+		return
+	}
+	h.Metrics.BeginHash()
 	result := login.Login(r.Context(), h.LoginStore, h.Pepper, login.Input{
 		Email:    logging.Redacted(req.Email),
 		Password: []byte(req.Password),
 	})
+	h.Metrics.EndHash()
+	h.HashLimiter.Release()
 
 	switch result.Outcome {
 	case login.OutcomeSuccess:
 		h.logger().Info("login: succeeded")
+		if result.RehashErr != nil {
+			h.logger().Warn("login: password hash upgrade failed", "error", result.RehashErr)
+		} else if result.Rehashed {
+			h.logger().Info("login: password hash upgraded to current parameters")
+		}
 		writeJSON(w, http.StatusOK, loginResponse{Status: "ok"})
 	default:
 		isError = true
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/password"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/registration"
@@ -31,9 +35,11 @@ var testPepper = []byte("test-pepper-not-a-real-secret")
 type fakeRegistrationStorage struct {
 	saveErr   error
 	deleteErr error
+	saved     []storage.UserRecord
 }
 
-func (f *fakeRegistrationStorage) SaveUser(_ context.Context, _ storage.UserRecord) error {
+func (f *fakeRegistrationStorage) SaveUser(_ context.Context, record storage.UserRecord) error {
+	f.saved = append(f.saved, record)
 	return f.saveErr
 }
 
@@ -61,6 +67,11 @@ func (f *fakeLoginStorage) GetPasswordHash(_ context.Context, _ string) (string,
 	return f.hash, f.err
 }
 
+func (f *fakeLoginStorage) UpdatePasswordHash(_ context.Context, _, _, newHash string) error {
+	f.hash = newHash
+	return nil
+}
+
 // newTestHandler builds a Handler wired to hand-written fakes and a
 // buffer-backed logger, so tests can both drive HTTP requests through it
 // and inspect exactly what was logged (DV-F-20's "without identifying the
@@ -123,6 +134,9 @@ func TestHandler_Register_Success(t *testing.T) {
 	if got := h.Metrics.Snapshot(); got.RequestCount != 1 || got.ErrorCount != 0 {
 		t.Fatalf("counters after success = %+v, want RequestCount=1, ErrorCount=0", got)
 	}
+	if got := h.Metrics.Snapshot().AverageEncryptionTimeMs; got <= 0 {
+		t.Fatalf("AverageEncryptionTimeMs after success = %v, want the email encryption recorded", got)
+	}
 }
 
 // Requirement: DV-F-12
@@ -350,3 +364,387 @@ func realStoredHash(t *testing.T) string {
 	}
 	return hash
 }
+
+// saturatedLimiter returns a password.Limiter whose only slot is already
+// held and which never waits, so the next Acquire fails immediately.
+func saturatedLimiter(t *testing.T) *password.Limiter {
+	t.Helper()
+
+	limiter, err := password.NewLimiter(1, 0)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v, want nil", err)
+	}
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() error = %v, want nil", err)
+	}
+	t.Cleanup(limiter.Release)
+	return limiter
+}
+
+// Requirement: DV-F-07
+func TestHandler_HashLimiterAtCapacityIsServiceUnavailable(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		store := &fakeRegistrationStorage{}
+		h, _ := newTestHandler(store, &fakePOSIX{}, &fakeLoginStorage{})
+		h.HashLimiter = saturatedLimiter(t)
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+		rec := httptest.NewRecorder()
+
+		h.Register(rec, req)
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+		if got := h.Metrics.Snapshot(); got.ErrorCount != 1 {
+			t.Fatalf("ErrorCount = %d, want 1", got.ErrorCount)
+		}
+	})
+
+	t.Run("login", func(t *testing.T) {
+		h, _ := newTestHandler(&fakeRegistrationStorage{}, &fakePOSIX{}, &fakeLoginStorage{hash: realStoredHash(t)})
+		h.HashLimiter = saturatedLimiter(t)
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, LoginPath, strings.NewReader(loginRequestBody(testEmail, testPassword)))
+		rec := httptest.NewRecorder()
+
+		h.Login(rec, req)
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// Requirement: DV-F-07
+func TestHandler_HashLimiterSlotIsReleased(t *testing.T) {
+	limiter, err := password.NewLimiter(1, 0)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v, want nil", err)
+	}
+	h, _ := newTestHandler(&fakeRegistrationStorage{}, &fakePOSIX{}, &fakeLoginStorage{hash: realStoredHash(t)})
+	h.HashLimiter = limiter
+
+	// Two sequential requests through a one-slot, zero-wait limiter only
+	// both succeed if the first one gave its slot back.
+	for i := range 2 {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, LoginPath, strings.NewReader(loginRequestBody(testEmail, testPassword)))
+		rec := httptest.NewRecorder()
+
+		h.Login(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+	}
+}
+
+// blockingLoginStorage is a hand-written fake implementing login.Storage
+// (CONTRIBUTING.md §7.5) whose GetPasswordHash signals entered and then
+// waits for release, so a test can observe a login mid-flight.
+type blockingLoginStorage struct {
+	fakeLoginStorage
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (f *blockingLoginStorage) GetPasswordHash(ctx context.Context, email string) (string, error) {
+	close(f.entered)
+	<-f.release
+	return f.fakeLoginStorage.GetPasswordHash(ctx, email)
+}
+
+// Requirement: DV-F-07
+// Requirement: DV-F-16
+func TestHandler_HashesInFlightRisesDuringHashAndFallsAfter(t *testing.T) {
+	loginStore := &blockingLoginStorage{
+		fakeLoginStorage: fakeLoginStorage{hash: realStoredHash(t)},
+		entered:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	h, _ := newTestHandler(&fakeRegistrationStorage{}, &fakePOSIX{}, &fakeLoginStorage{})
+	h.LoginStore = loginStore
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, LoginPath, strings.NewReader(loginRequestBody(testEmail, testPassword)))
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		h.Login(rec, req)
+		close(done)
+	}()
+
+	<-loginStore.entered
+	if got := h.Metrics.Snapshot().HashesInFlight; got != 1 {
+		t.Fatalf("HashesInFlight during the login = %d, want 1", got)
+	}
+
+	close(loginStore.release)
+	<-done
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := h.Metrics.Snapshot().HashesInFlight; got != 0 {
+		t.Fatalf("HashesInFlight after the login = %d, want 0", got)
+	}
+
+	// Register hashes synchronously, so only its aftermath is observable.
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec = httptest.NewRecorder()
+	h.Register(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if got := h.Metrics.Snapshot().HashesInFlight; got != 0 {
+		t.Fatalf("HashesInFlight after the registration = %d, want 0", got)
+	}
+}
+
+// Requirement: DV-F-12
+func TestHandler_RegisterMinDurationEqualizesOutcomes(t *testing.T) {
+	const minDuration = 300 * time.Millisecond
+
+	elapsed := func(store *fakeRegistrationStorage, wantStatus int) time.Duration {
+		t.Helper()
+		h, _ := newTestHandler(store, &fakePOSIX{}, &fakeLoginStorage{})
+		h.RegisterMinDuration = minDuration
+
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+		rec := httptest.NewRecorder()
+
+		start := time.Now()
+		h.Register(rec, req)
+		took := time.Since(start)
+
+		if rec.Code != wantStatus {
+			t.Fatalf("status = %d, want %d", rec.Code, wantStatus)
+		}
+		return took
+	}
+
+	duplicate := elapsed(&fakeRegistrationStorage{saveErr: storage.ErrDuplicateUser}, http.StatusConflict)
+	registered := elapsed(&fakeRegistrationStorage{}, http.StatusCreated)
+
+	for name, took := range map[string]time.Duration{"duplicate": duplicate, "registered": registered} {
+		if took < minDuration {
+			t.Fatalf("%s registration took %v, want at least RegisterMinDuration (%v)", name, took, minDuration)
+		}
+	}
+	if diff := (duplicate - registered).Abs(); diff > 100*time.Millisecond {
+		t.Fatalf("duplicate took %v and registered took %v, want them within 100ms of each other", duplicate, registered)
+	}
+}
+
+// Requirement: DV-F-20
+func TestHandler_RegisterMinDurationSkipsValidationFailures(t *testing.T) {
+	h, _ := newTestHandler(&fakeRegistrationStorage{}, &fakePOSIX{}, &fakeLoginStorage{})
+	h.RegisterMinDuration = time.Hour
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody("not-an-email", testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		h.Register(rec, req)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Register() padded a validation failure, want it answered immediately")
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// Requirement: DV-F-08
+func TestHandler_Register_DatabaseUnavailableIsServiceUnavailable(t *testing.T) {
+	saveErr := fmt.Errorf("%w: begin transaction: %w", storage.ErrDatabaseUnavailable, context.DeadlineExceeded)
+	h, _ := newTestHandler(&fakeRegistrationStorage{saveErr: saveErr}, &fakePOSIX{}, &fakeLoginStorage{})
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d for a retryable database outage", rec.Code, http.StatusServiceUnavailable)
+	}
+}
+
+// Requirement: DV-F-08
+func TestHandler_Register_CallerCanceledIsNotLoggedAsError(t *testing.T) {
+	saveErr := fmt.Errorf("storage: begin transaction: %w", context.Canceled)
+	h, logBuf := newTestHandler(&fakeRegistrationStorage{saveErr: saveErr}, &fakePOSIX{}, &fakeLoginStorage{})
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+
+	logs := logBuf.String()
+	if strings.Contains(logs, "level=ERROR") {
+		t.Fatalf("log contains an ERROR line for a caller cancellation: %s", logs)
+	}
+	if !strings.Contains(logs, "register: caller went away") {
+		t.Fatalf("log = %q, want the caller-went-away line", logs)
+	}
+	if snap := h.Metrics.Snapshot(); snap.ErrorCount != 0 {
+		t.Fatalf("ErrorCount = %d, want 0 for a caller cancellation", snap.ErrorCount)
+	}
+}
+
+// panicStorage is a registration.Storage that fails the test if called,
+// for asserting a request was rejected before any work was done.
+type panicStorage struct{ t *testing.T }
+
+func (p panicStorage) SaveUser(context.Context, storage.UserRecord) error {
+	p.t.Fatal("SaveUser called during maintenance mode")
+	return nil
+}
+
+func (p panicStorage) DeleteUser(context.Context, string) error {
+	p.t.Fatal("DeleteUser called during maintenance mode")
+	return nil
+}
+
+// Requirement: DV-F-08
+func TestHandler_MaintenanceRejectsRegistrationButNotLogin(t *testing.T) {
+	flag := filepath.Join(t.TempDir(), "maintenance")
+	if err := os.WriteFile(flag, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	h, _ := newTestHandler(panicStorage{t: t}, &fakePOSIX{}, &fakeLoginStorage{hash: realStoredHash(t)})
+	h.Maintenance = FileMaintenance{Path: flag}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Register status = %d, want %d during maintenance", rec.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(rec.Body.String(), "maintenance") {
+		t.Fatalf("Register body = %s, want the distinct maintenance message", rec.Body.String())
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodPost, LoginPath, strings.NewReader(loginRequestBody(testEmail, testPassword)))
+	rec = httptest.NewRecorder()
+	h.Login(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Login status = %d, want %d (reads keep serving during maintenance)", rec.Code, http.StatusOK)
+	}
+
+	// Leaving maintenance mode lets registrations through again.
+	if err := os.Remove(flag); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	h.Store = &fakeRegistrationStorage{}
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec = httptest.NewRecorder()
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Register status = %d, want %d after maintenance ends", rec.Code, http.StatusCreated)
+	}
+}
+
+// Requirement: DV-F-02
+func TestHandler_Register_BlocklistedSSHKeyIsRejected(t *testing.T) {
+	h, logBuf := newTestHandler(panicStorage{t: t}, &fakePOSIX{}, &fakeLoginStorage{})
+	h.SSHKeyBlocklist = SSHKeyBlocklist{testKeyFingerprint(t): {}}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if strings.Contains(logBuf.String(), testEmail) {
+		t.Fatalf("log contains the email: %s", logBuf.String())
+	}
+}
+
+// Requirement: DV-F-02
+func TestHandler_Register_SSHKeyComments(t *testing.T) {
+	const keyWithoutComment = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJl6r+SEQfM50WkfR/4iZpu9NDXCBs4RwIKidjhOCbdw"
+
+	tests := []struct {
+		name       string
+		policy     SSHKeyCommentPolicy
+		sshKey     string
+		wantStatus int
+		wantStored string
+	}{
+		{name: "keep stores the comment", policy: SSHKeyCommentKeep, sshKey: testSSHPublicKey, wantStatus: http.StatusCreated, wantStored: testSSHPublicKey},
+		{name: "strip removes the comment", policy: SSHKeyCommentStrip, sshKey: testSSHPublicKey, wantStatus: http.StatusCreated, wantStored: keyWithoutComment},
+		{name: "strip leaves a comment-free key", policy: SSHKeyCommentStrip, sshKey: keyWithoutComment, wantStatus: http.StatusCreated, wantStored: keyWithoutComment},
+		{name: "reject refuses a comment", policy: SSHKeyCommentReject, sshKey: testSSHPublicKey, wantStatus: http.StatusBadRequest},
+		{name: "reject accepts a comment-free key", policy: SSHKeyCommentReject, sshKey: keyWithoutComment, wantStatus: http.StatusCreated, wantStored: keyWithoutComment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeRegistrationStorage{}
+			h, logBuf := newTestHandler(store, &fakePOSIX{}, &fakeLoginStorage{})
+			h.SSHKeyComments = tt.policy
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, tt.sshKey)))
+			rec := httptest.NewRecorder()
+
+			h.Register(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStored == "" {
+				if len(store.saved) != 0 {
+					t.Fatalf("SaveUser called %d times, want 0", len(store.saved))
+				}
+				if strings.Contains(logBuf.String(), "user@client") {
+					t.Fatalf("log contains the key comment: %s", logBuf.String())
+				}
+				return
+			}
+			if len(store.saved) != 1 || store.saved[0].SSHPublicKey != tt.wantStored {
+				t.Fatalf("stored records = %+v, want one with SSHPublicKey %q", store.saved, tt.wantStored)
+			}
+		})
+	}
+}
+
+// fakeEncryptionHealth is a hand-written fake of EncryptionHealth
+// (CONTRIBUTING.md §7.5).
+type fakeEncryptionHealth struct{ healthy bool }
+
+func (f fakeEncryptionHealth) Healthy() bool { return f.healthy }
+
+// Requirement: DV-F-04
+func TestHandler_FailingEncryptionSelfTestRejectsRegistrationButNotLogin(t *testing.T) {
+	h, logBuf := newTestHandler(panicStorage{t: t}, &fakePOSIX{}, &fakeLoginStorage{err: storage.ErrUserNotFound})
+	h.EncryptionHealth = fakeEncryptionHealth{healthy: false}
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("register status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(logBuf.String(), "level=ERROR") {
+		t.Fatalf("log = %q, want an ERROR line for the failing self-test", logBuf.String())
+	}
+
+	req = httptest.NewRequestWithContext(context.Background(), http.MethodPost, LoginPath, strings.NewReader(loginRequestBody(testEmail, testPassword)))
+	rec = httptest.NewRecorder()
+	h.Login(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("login status = %d, want %d (login is not gated by the self-test)", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,80 @@
+package httpapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// testKeyFingerprint returns testSSHPublicKey's SHA256 fingerprint.
+func testKeyFingerprint(t *testing.T) string {
+	t.Helper()
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(testSSHPublicKey))
+	if err != nil {
+		t.Fatalf("ParseAuthorizedKey() error = %v", err)
+	}
+	return ssh.FingerprintSHA256(publicKey)
+}
+
+// Requirement: DV-F-02
+func TestLoadSSHKeyBlocklist(t *testing.T) {
+	fingerprint := testKeyFingerprint(t)
+
+	tests := []struct {
+		name        string
+		contents    string
+		wantErr     bool
+		wantBlocked bool
+	}{
+		{name: "listed fingerprint with comments and blank lines", contents: "# Debian weak keys\n\n" + fingerprint + "\n", wantBlocked: true},
+		{name: "other fingerprint", contents: "SHA256:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA\n", wantBlocked: false},
+		{name: "empty file", contents: "", wantBlocked: false},
+		{name: "MD5 fingerprint is rejected", contents: "MD5:16:27:ac:a5:76:28:2d:36:63:1b:56:4d:eb:df:a6:48\n", wantErr: true},
+		{name: "whole key line is rejected", contents: testSSHPublicKey + "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "blocklist")
+			if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			blocklist, err := LoadSSHKeyBlocklist(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadSSHKeyBlocklist() error = nil, want non-nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSSHKeyBlocklist() error = %v", err)
+			}
+			if got := blocklist.Blocked(testSSHPublicKey); got != tt.wantBlocked {
+				t.Fatalf("Blocked() = %v, want %v", got, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+// Requirement: DV-F-02
+func TestLoadSSHKeyBlocklist_MissingFileFails(t *testing.T) {
+	if _, err := LoadSSHKeyBlocklist(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("LoadSSHKeyBlocklist() error = nil, want non-nil for a missing file")
+	}
+}
+
+// Requirement: DV-F-02
+func TestSSHKeyBlocklist_Blocked_NilAndUnparseable(t *testing.T) {
+	var nilBlocklist SSHKeyBlocklist
+	if nilBlocklist.Blocked(testSSHPublicKey) {
+		t.Fatal("nil blocklist Blocked() = true, want false")
+	}
+
+	blocklist := SSHKeyBlocklist{testKeyFingerprint(t): {}}
+	if blocklist.Blocked("not a key") {
+		t.Fatal("Blocked() = true for an unparseable key, want false")
+	}
+}
@@ -0,0 +1,70 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// errSSHKeyCommentNotAllowed is the internal error behind every
+// registration refused under SSHKeyCommentReject, for logging only. Like
+// errSSHKeyBlocklisted it carries no part of the key or its comment.
+var errSSHKeyCommentNotAllowed = errors.New("httpapi: ssh public key comment is not allowed")
+
+// SSHKeyCommentPolicy decides what Handler.Register does with the
+// optional trailing comment of a registered SSH public key. The comment
+// is free text ssh-keygen fills with "user@host" by default, so it can
+// carry a corporate username or hostname the key's owner never meant to
+// hand over; the key itself is all Storage-Service's authentication
+// needs.
+type SSHKeyCommentPolicy int
+
+const (
+	// SSHKeyCommentKeep stores the key exactly as submitted, comment
+	// included. The zero value, so an unconfigured Handler keeps today's
+	// behavior.
+	SSHKeyCommentKeep SSHKeyCommentPolicy = iota
+
+	// SSHKeyCommentStrip stores the key without its comment.
+	SSHKeyCommentStrip
+
+	// SSHKeyCommentReject answers a key with a comment with HTTP 400.
+	SSHKeyCommentReject
+)
+
+// ParseSSHKeyCommentPolicy parses "keep", "strip", or "reject".
+func ParseSSHKeyCommentPolicy(s string) (SSHKeyCommentPolicy, error) {
+	switch s {
+	case "keep":
+		return SSHKeyCommentKeep, nil
+	case "strip":
+		return SSHKeyCommentStrip, nil
+	case "reject":
+		return SSHKeyCommentReject, nil
+	default:
+		return 0, fmt.Errorf("httpapi: unknown ssh key comment policy %q, want keep, strip, or reject", s)
+	}
+}
+
+// apply returns authorizedKey, an OpenSSH authorized_keys line that
+// already passed validation.ValidateRegister, as it should be stored
+// under p, or errSSHKeyCommentNotAllowed. A key without a comment is
+// returned unchanged under every policy.
+func (p SSHKeyCommentPolicy) apply(authorizedKey string) (string, error) {
+	if p == SSHKeyCommentKeep {
+		return authorizedKey, nil
+	}
+	publicKey, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+	if err != nil {
+		return "", fmt.Errorf("httpapi: parse ssh public key: %w", err)
+	}
+	if comment == "" {
+		return authorizedKey, nil
+	}
+	if p == SSHKeyCommentReject {
+		return "", errSSHKeyCommentNotAllowed
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))), nil
+}
@@ -0,0 +1,33 @@
+package httpapi
+
+import "testing"
+
+// Requirement: DV-F-02
+func TestParseSSHKeyCommentPolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    SSHKeyCommentPolicy
+		wantErr bool
+	}{
+		{value: "keep", want: SSHKeyCommentKeep},
+		{value: "strip", want: SSHKeyCommentStrip},
+		{value: "reject", want: SSHKeyCommentReject},
+		{value: "Strip", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSSHKeyCommentPolicy(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSSHKeyCommentPolicy(%q) error = nil, want non-nil", tt.value)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseSSHKeyCommentPolicy(%q) = (%v, %v), want (%v, nil)", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package httpapi
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// errMaintenance is the internal error behind every maintenance-mode
+// rejection, for logging only.
+var errMaintenance = errors.New("httpapi: maintenance mode is active")
+
+// Maintenance reports whether Database-Vault is in maintenance mode, in
+// which Handler.Register rejects every request before doing any work
+// while Handler.Login and PublicKeyHandler, which only read, keep
+// serving.
+type Maintenance interface {
+	Active() bool
+}
+
+// FileMaintenance is a Maintenance an operator toggles by creating and
+// removing the file at Path (e.g. `touch`/`rm` inside the container) - a
+// switch that needs no admin endpoint, which this service does not have,
+// and no restart. An empty Path is never active.
+type FileMaintenance struct {
+	Path string
+}
+
+// Active reports whether Path exists. Any Stat failure other than the
+// file not existing (e.g. a permission error) also counts as active:
+// per RD-04, when it is unclear whether an operator asked for writes to
+// stop, they stop.
+func (m FileMaintenance) Active() bool {
+	if m.Path == "" {
+		return false
+	}
+	_, err := os.Stat(m.Path)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// errEncryptionUnhealthy is the internal error behind every rejection
+// while the encryption self-test fails, for logging only.
+var errEncryptionUnhealthy = errors.New("httpapi: encryption self-test is failing")
+
+// EncryptionHealth reports whether email encryption can be trusted, e.g.
+// an *encryption.SelfTest. Like Maintenance, an unhealthy state stops
+// Handler.Register only.
+type EncryptionHealth interface {
+	Healthy() bool
+}
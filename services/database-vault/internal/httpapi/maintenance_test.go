@@ -0,0 +1,35 @@
+package httpapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Requirement: DV-F-08
+func TestFileMaintenance_Active(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintenance")
+	m := FileMaintenance{Path: path}
+
+	if m.Active() {
+		t.Fatal("Active() = true before the file exists")
+	}
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if !m.Active() {
+		t.Fatal("Active() = false while the file exists")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if m.Active() {
+		t.Fatal("Active() = true after the file was removed")
+	}
+
+	if (FileMaintenance{}).Active() {
+		t.Fatal("Active() = true for an empty Path")
+	}
+}
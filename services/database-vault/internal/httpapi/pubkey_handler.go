@@ -37,16 +37,26 @@
 // from "the lookup itself failed, treat as fail-secure/deny too" for its
 // own logging, even though both ultimately result in denying the
 // connection either way (RD-04).
+//
+// The same listener also serves VerifySSHKeyPath, the reverse lookup: given
+// a public key rather than a username, which user (by email_hash, never the
+// email) does it belong to. The same reasoning allows its distinct 404.
 package httpapi
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/ssh"
+
 	apperrors "github.com/Verryx-02/RAM-USB/pkg/errors"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/storage"
 )
@@ -55,13 +65,12 @@ import (
 // dedicated mux/listener (see internal/server.NewPublicKeyTLSConfig's doc
 // comment for why this runs on a separate listener from RegisterPath/
 // LoginPath, not merely a separate path on the same one). Unlike
-// RegisterPath/LoginPath, this pattern includes the "GET " method prefix
-// supported by net/http.ServeMux's enhanced routing (Go 1.22+) plus a
-// {posix_username} path wildcard, read via (*http.Request).PathValue —
-// there is no existing RegisterPath/LoginPath-style convention to match
-// here since this endpoint's shape (a path parameter, not a JSON body) is
-// new.
-const PublicKeyPath = "GET /internal/v1/public-key/{posix_username}"
+// RegisterPath/LoginPath, this pattern includes a {posix_username} path
+// wildcard supported by net/http.ServeMux's enhanced routing (Go 1.22+),
+// read via (*http.Request).PathValue. Like them, it carries no method
+// prefix: main.go restricts it to GET/HEAD with httpmethod.Allow, so a
+// wrong method is logged and answered the same way on every endpoint.
+const PublicKeyPath = "/internal/v1/public-key/{posix_username}"
 
 // posixUsernamePattern re-validates the path parameter against DV-F-09's
 // exact "user<xxxxxx>" format (six lowercase base-36 characters) before
@@ -77,18 +86,36 @@ var posixUsernamePattern = regexp.MustCompile(`^user[0-9a-z]{6}$`)
 // posixUsernamePattern.
 var errMalformedPosixUsername = errors.New("public-key: malformed posix username")
 
+// VerifySSHKeyPath is the pattern VerifySSHKey is registered under, on the
+// same listener as PublicKeyPath. main.go restricts it to POST: the key is
+// sent in the body, {"ssh_public_key": "<authorized_keys line>"}, since an
+// authorized_keys line does not fit in a path segment.
+const VerifySSHKeyPath = "/internal/v1/verify-ssh-key"
+
+// maxVerifySSHKeyBodyBytes bounds VerifySSHKey's request body. One
+// authorized_keys line is at most about 825 bytes for RSA-4096 (see
+// pkg/validation's maxPayloadBytes), so 2 KiB leaves the same headroom.
+const maxVerifySSHKeyBodyBytes = 2048
+
+// errMalformedVerifySSHKeyRequest is logged, without the body, when
+// VerifySSHKey's request is oversized, not the expected JSON object, or
+// does not carry a parseable public key.
+var errMalformedVerifySSHKeyRequest = errors.New("verify-ssh-key: malformed request")
+
 // PublicKeyStore is the minimal interface PublicKeyHandler needs: one
-// lookup by posix_username. Depending on this narrow interface, instead of
+// lookup by posix_username and one by ssh public key. Depending on this narrow interface, instead of
 // storage.Querier or *pgxpool.Pool directly, lets unit tests substitute a
 // hand-written fake per CONTRIBUTING.md §7.5 — same "narrow interface +
 // adapter over a free function" shape as registration.Storage/
 // login.Storage's own adapters.
 type PublicKeyStore interface {
 	GetSSHPublicKey(ctx context.Context, posixUsername string) (string, error)
+	GetEmailHashBySSHPublicKey(ctx context.Context, sshPublicKey string) (string, error)
 }
 
-// PublicKeyStoreAdapter adapts storage.GetSSHPublicKeyByPosixUsername (a
-// free function taking a storage.Querier) to PublicKeyStore.
+// PublicKeyStoreAdapter adapts storage.GetSSHPublicKeyByPosixUsername and
+// storage.GetEmailHashBySSHPublicKey (free functions taking a
+// storage.Querier) to PublicKeyStore.
 type PublicKeyStoreAdapter struct {
 	DB storage.Querier
 }
@@ -98,6 +125,11 @@ func (a PublicKeyStoreAdapter) GetSSHPublicKey(ctx context.Context, posixUsernam
 	return storage.GetSSHPublicKeyByPosixUsername(ctx, a.DB, posixUsername)
 }
 
+// GetEmailHashBySSHPublicKey implements PublicKeyStore.
+func (a PublicKeyStoreAdapter) GetEmailHashBySSHPublicKey(ctx context.Context, sshPublicKey string) (string, error) {
+	return storage.GetEmailHashBySSHPublicKey(ctx, a.DB, sshPublicKey)
+}
+
 // PublicKeyHandler implements ST-F-11's receiving side: Storage-Service's
 // (not yet built) AuthorizedKeysCommand calls this over the mTLS listener
 // internal/server.NewPublicKeyTLSConfig configures.
@@ -173,3 +205,78 @@ func (h *PublicKeyHandler) PublicKey(w http.ResponseWriter, r *http.Request) {
 type publicKeyResponse struct {
 	SSHPublicKey string `json:"ssh_public_key"`
 }
+
+// verifySSHKeyRequest is the JSON body VerifySSHKey accepts.
+type verifySSHKeyRequest struct {
+	SSHPublicKey string `json:"ssh_public_key"`
+}
+
+// verifySSHKeyResponse is the JSON body VerifySSHKey writes on a match.
+type verifySSHKeyResponse struct {
+	EmailHash string `json:"email_hash"`
+}
+
+// VerifySSHKey lets Storage-Service confirm that an SSH public key belongs
+// to a registered user, and learn which one by email_hash (DV-F-03) for its
+// own access control. The key may carry a comment; only its type and
+// base64 body are compared, so a key registered as "ssh-ed25519 AAAA...
+// alice@laptop" matches "ssh-ed25519 AAAA..." and vice versa. A miss,
+// including a key registered to more than one user, is HTTP 404 with the
+// generic not-found body and nothing else. Neither the key nor the email
+// hash is ever logged.
+func (h *PublicKeyHandler) VerifySSHKey(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	h.Metrics.BeginRequest()
+	isError := false
+	defer func() {
+		h.Metrics.EndRequest(time.Since(start), isError)
+	}()
+
+	sshPublicKey, err := decodeVerifySSHKeyRequest(r.Body)
+	if err != nil {
+		isError = true
+		h.logger().Warn("verify-ssh-key: rejected malformed request")
+		writeAppError(w, apperrors.NewBadRequest(err))
+		return
+	}
+
+	emailHash, err := h.Store.GetEmailHashBySSHPublicKey(r.Context(), sshPublicKey)
+	if err != nil {
+		isError = true
+		if errors.Is(err, storage.ErrSSHPublicKeyNotFound) {
+			h.logger().Info("verify-ssh-key: not found")
+			writeAppError(w, apperrors.NewNotFound(err))
+			return
+		}
+		h.logger().Error("verify-ssh-key: lookup failed", "error", err)
+		writeAppError(w, apperrors.NewInternal(err))
+		return
+	}
+
+	h.logger().Info("verify-ssh-key: succeeded")
+	writeJSON(w, http.StatusOK, verifySSHKeyResponse{EmailHash: emailHash})
+}
+
+// decodeVerifySSHKeyRequest reads a verifySSHKeyRequest from body and
+// returns its key reduced to "<type> <base64>", the form
+// storage.GetEmailHashBySSHPublicKey matches on. Any failure is
+// errMalformedVerifySSHKeyRequest, so the caller never logs the body.
+func decodeVerifySSHKeyRequest(body io.Reader) (string, error) {
+	raw, err := io.ReadAll(io.LimitReader(body, maxVerifySSHKeyBodyBytes+1))
+	if err != nil || len(raw) > maxVerifySSHKeyBodyBytes {
+		return "", errMalformedVerifySSHKeyRequest
+	}
+
+	var req verifySSHKeyRequest
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return "", errMalformedVerifySSHKeyRequest
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(req.SSHPublicKey))
+	if err != nil {
+		return "", errMalformedVerifySSHKeyRequest
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))), nil
+}
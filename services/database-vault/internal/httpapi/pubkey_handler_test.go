@@ -17,14 +17,22 @@ import (
 // fakePublicKeyStore is a hand-written fake implementing PublicKeyStore
 // (CONTRIBUTING.md §7.5).
 type fakePublicKeyStore struct {
-	key string
-	err error
+	key       string
+	emailHash string
+	err       error
+
+	gotSSHPublicKey string
 }
 
 func (f *fakePublicKeyStore) GetSSHPublicKey(_ context.Context, _ string) (string, error) {
 	return f.key, f.err
 }
 
+func (f *fakePublicKeyStore) GetEmailHashBySSHPublicKey(_ context.Context, sshPublicKey string) (string, error) {
+	f.gotSSHPublicKey = sshPublicKey
+	return f.emailHash, f.err
+}
+
 // newTestPublicKeyHandler builds a PublicKeyHandler wired to a
 // hand-written fake and a buffer-backed logger, mirroring
 // newTestHandler's shape in handler_test.go.
@@ -166,3 +174,91 @@ func TestPublicKeyHandler_MetricsCounted(t *testing.T) {
 		t.Fatalf("ErrorCount = %d, want 0", snap.ErrorCount)
 	}
 }
+
+// doVerifySSHKeyRequest POSTs body to VerifySSHKeyPath through a real
+// http.ServeMux, same as doPublicKeyRequest.
+func doVerifySSHKeyRequest(h *PublicKeyHandler, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc(VerifySSHKeyPath, h.VerifySSHKey)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, VerifySSHKeyPath, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+// Requirement: ST-F-11
+func TestPublicKeyHandler_VerifySSHKeyFound(t *testing.T) {
+	const wantEmailHash = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	store := &fakePublicKeyStore{emailHash: wantEmailHash}
+	h, logBuf := newTestPublicKeyHandler(store)
+	rec := doVerifySSHKeyRequest(h, `{"ssh_public_key":"`+testSSHPublicKey+`"}`)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var body struct {
+		EmailHash string `json:"email_hash"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response body: %v", err)
+	}
+	if body.EmailHash != wantEmailHash {
+		t.Fatalf("email_hash = %q, want %q", body.EmailHash, wantEmailHash)
+	}
+
+	// The store is queried with the key's comment ("user@client") removed.
+	if want := strings.TrimSuffix(testSSHPublicKey, " user@client"); store.gotSSHPublicKey != want {
+		t.Fatalf("store queried with %q, want %q", store.gotSSHPublicKey, want)
+	}
+	if strings.Contains(logBuf.String(), wantEmailHash) || strings.Contains(logBuf.String(), "AAAAC3Nza") {
+		t.Fatalf("log contains the email hash or key: %s", logBuf.String())
+	}
+}
+
+// Requirement: ST-F-11
+func TestPublicKeyHandler_VerifySSHKeyNotFoundRevealsNothing(t *testing.T) {
+	h, _ := newTestPublicKeyHandler(&fakePublicKeyStore{err: storage.ErrSSHPublicKeyNotFound})
+	rec := doVerifySSHKeyRequest(h, `{"ssh_public_key":"`+testSSHPublicKey+`"}`)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response body: %v", err)
+	}
+	if len(body) != 1 || body["error"] != "the requested resource was not found" {
+		t.Fatalf("body = %v, want only the generic not-found error", body)
+	}
+}
+
+// Requirement: ST-F-11
+func TestPublicKeyHandler_VerifySSHKeyMalformedRequestRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "not JSON", body: `ssh-ed25519 AAAA`},
+		{name: "unknown field", body: `{"ssh_public_key":"` + testSSHPublicKey + `","email":"a@example.com"}`},
+		{name: "unparseable key", body: `{"ssh_public_key":"ssh-ed25519 not-base64"}`},
+		{name: "oversized body", body: `{"ssh_public_key":"` + strings.Repeat("a", 3000) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakePublicKeyStore{err: errors.New("store must not be called for a malformed request")}
+			h, _ := newTestPublicKeyHandler(store)
+			rec := doVerifySSHKeyRequest(h, tt.body)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if store.gotSSHPublicKey != "" {
+				t.Fatal("store was queried for a malformed request")
+			}
+		})
+	}
+}
@@ -12,11 +12,19 @@ import (
 // Storage. Same "narrow interface + adapter over a free function" pattern
 // as the registration package's StorageAdapter/POSIXAdapter over
 // storage.SaveUser/storage.DeleteUser/posix.CreatePOSIXUser.
+//
+// Writer carries UpdatePasswordHash's single UPDATE.
 type StorageAdapter struct {
-	DB storage.Querier
+	DB     storage.Querier
+	Writer storage.Execer
 }
 
 // GetPasswordHash implements Storage.
 func (a StorageAdapter) GetPasswordHash(ctx context.Context, emailHash string) (string, error) {
 	return storage.GetPasswordHash(ctx, a.DB, emailHash)
 }
+
+// UpdatePasswordHash implements Storage.
+func (a StorageAdapter) UpdatePasswordHash(ctx context.Context, emailHash, oldHash, newHash string) error {
+	return storage.UpdatePasswordHash(ctx, a.Writer, emailHash, oldHash, newHash)
+}
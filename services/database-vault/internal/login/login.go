@@ -31,6 +31,7 @@ package login
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/hashing"
@@ -98,19 +99,35 @@ var ErrPasswordVerificationFailed = errors.New("login: password verification fai
 // case DV-F-15 does not require hiding, so long as the value itself
 // carries no per-record content (see ErrPasswordVerificationFailed's doc
 // comment).
+//
+// Rehashed and RehashErr report, on OutcomeSuccess only, whether the stored
+// hash was upgraded to the current Argon2id parameters (see
+// password.NeedsRehash). A failed upgrade does not fail the login.
 type Result struct {
 	Outcome Outcome
 	Err     error
+
+	Rehashed  bool
+	RehashErr error
 }
 
+// ErrRehashFailed wraps every Result.RehashErr. The error it wraps comes
+// from salt generation, hashing, or the UPDATE, none of which embeds the
+// password, the hash, or the email hash.
+var ErrRehashFailed = errors.New("login: password hash upgrade failed")
+
 // Storage is the subset of persistence Login needs: retrieving the stored
 // password_hash for an email hash (DV-F-13). Depending on this narrow
 // interface, rather than on storage.Querier directly, lets tests
 // substitute a hand-written fake (CONTRIBUTING.md §7.5) without a real
 // database — same pattern as the registration package's Storage interface
 // over storage.Beginner.
+//
+// UpdatePasswordHash replaces the stored hash after a successful login
+// whose hash used outdated parameters, but only if it is still oldHash.
 type Storage interface {
 	GetPasswordHash(ctx context.Context, emailHash string) (string, error)
+	UpdatePasswordHash(ctx context.Context, emailHash, oldHash, newHash string) error
 }
 
 // Input holds the credentials a login request supplies. Email is typed as
@@ -137,6 +154,11 @@ type Input struct {
 // but distinguishable in a log for operational purposes, and carrying none
 // of VerifyPassword's own error content (see ErrPasswordVerificationFailed's
 // doc comment). Only a positive match returns OutcomeSuccess.
+//
+// On a match whose stored hash predates the current Argon2id parameters,
+// Login also re-hashes the password with a fresh salt and stores the
+// result - the only moment the plaintext needed to do so is available.
+// This costs one extra Argon2id computation, once per such user.
 func Login(ctx context.Context, store Storage, pepper []byte, input Input) Result {
 	emailHash := hashing.HashEmail(input.Email)
 
@@ -166,5 +188,28 @@ func Login(ctx context.Context, store Storage, pepper []byte, input Input) Resul
 		return Result{Outcome: OutcomeUnauthorized, Err: ErrAuthenticationFailed}
 	}
 
-	return Result{Outcome: OutcomeSuccess}
+	if !password.NeedsRehash(storedHash) {
+		return Result{Outcome: OutcomeSuccess}
+	}
+	if err := rehash(ctx, store, pepper, emailHash, storedHash, input.Password); err != nil {
+		return Result{Outcome: OutcomeSuccess, RehashErr: err}
+	}
+	return Result{Outcome: OutcomeSuccess, Rehashed: true}
+}
+
+// rehash hashes plaintext with the current parameters and a fresh salt,
+// and stores it in place of storedHash.
+func rehash(ctx context.Context, store Storage, pepper []byte, emailHash, storedHash string, plaintext []byte) error {
+	salt, err := password.GenerateSalt()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRehashFailed, err)
+	}
+	newHash, err := password.HashPassword(plaintext, salt, pepper)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRehashFailed, err)
+	}
+	if err := store.UpdatePasswordHash(ctx, emailHash, storedHash, newHash); err != nil {
+		return fmt.Errorf("%w: %w", ErrRehashFailed, err)
+	}
+	return nil
 }
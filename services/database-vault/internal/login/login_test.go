@@ -2,10 +2,14 @@ package login
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
+	"golang.org/x/crypto/argon2"
+
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/hashing"
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/password"
@@ -17,8 +21,11 @@ var testPepper = []byte("test-pepper-not-a-real-secret")
 // fakeStorage is a hand-written fake implementing this package's Storage
 // interface (CONTRIBUTING.md §7.5): it returns a fixed stored hash keyed by
 // email hash, or a fixed "not found" error, without a real database.
+// UpdatePasswordHash replaces the map entry, or returns updateErr.
 type fakeStorage struct {
-	hashes map[string]string
+	hashes      map[string]string
+	updateErr   error
+	updateCalls int
 }
 
 func (f *fakeStorage) GetPasswordHash(_ context.Context, emailHash string) (string, error) {
@@ -29,6 +36,17 @@ func (f *fakeStorage) GetPasswordHash(_ context.Context, emailHash string) (stri
 	return hash, nil
 }
 
+func (f *fakeStorage) UpdatePasswordHash(_ context.Context, emailHash, oldHash, newHash string) error {
+	f.updateCalls++
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	if f.hashes[emailHash] == oldHash {
+		f.hashes[emailHash] = newHash
+	}
+	return nil
+}
+
 // errNotFound simulates storage.ErrUserNotFound without importing the
 // storage package here — login.Storage only needs an error to exist, its
 // identity/wrapping is storage's concern, not login's (DV-F-15: login must
@@ -192,3 +210,79 @@ func TestLogin_MalformedStoredHash_TreatedAsUnauthorized(t *testing.T) {
 		t.Fatalf("Err.Error() = %q leaks the fixture's malformed stored hash content", got)
 	}
 }
+
+// newOldParameterHash builds a PHC hash for testEmail/testPassword under
+// weaker Argon2id parameters (t=1, 19 MiB) than password.HashPassword's
+// current ones, as a record created before a parameter upgrade would hold.
+func newOldParameterHash(t *testing.T) string {
+	t.Helper()
+	salt, err := password.GenerateSalt()
+	if err != nil {
+		t.Fatalf("GenerateSalt: %v", err)
+	}
+	const time, memoryKiB, threads = 1, 19456, 1
+	digest := argon2.IDKey([]byte(testPassword+string(testPepper)), salt, time, memoryKiB, threads, 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, memoryKiB, time, threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(digest))
+}
+
+// Requirement: DV-F-14
+func TestLogin_OldParameterHashIsUpgraded(t *testing.T) {
+	emailHash := hashing.HashEmail(logging.Redacted(testEmail))
+	oldHash := newOldParameterHash(t)
+	store := &fakeStorage{hashes: map[string]string{emailHash: oldHash}}
+
+	result := Login(context.Background(), store, testPepper, Input{Email: logging.Redacted(testEmail), Password: []byte(testPassword)})
+
+	if result.Outcome != OutcomeSuccess || !result.Rehashed || result.RehashErr != nil {
+		t.Fatalf("Login() = %+v, want a successful, rehashed login", result)
+	}
+	upgraded := store.hashes[emailHash]
+	if upgraded == oldHash || password.NeedsRehash(upgraded) {
+		t.Fatalf("stored hash = %q, want one under the current parameters", upgraded)
+	}
+
+	// The upgraded hash still verifies, and needs no further upgrade.
+	again := Login(context.Background(), store, testPepper, Input{Email: logging.Redacted(testEmail), Password: []byte(testPassword)})
+	if again.Outcome != OutcomeSuccess || again.Rehashed {
+		t.Fatalf("second Login() = %+v, want success without a rehash", again)
+	}
+	if store.updateCalls != 1 {
+		t.Fatalf("UpdatePasswordHash called %d times, want 1", store.updateCalls)
+	}
+}
+
+// Requirement: DV-F-14
+func TestLogin_CurrentHashAndFailedLoginAreNotUpgraded(t *testing.T) {
+	emailHash := hashing.HashEmail(logging.Redacted(testEmail))
+
+	current := &fakeStorage{hashes: map[string]string{emailHash: newStoredHash(t)}}
+	if result := Login(context.Background(), current, testPepper, Input{Email: logging.Redacted(testEmail), Password: []byte(testPassword)}); result.Rehashed {
+		t.Fatalf("Login() = %+v, want no rehash for a current-parameter hash", result)
+	}
+
+	old := &fakeStorage{hashes: map[string]string{emailHash: newOldParameterHash(t)}}
+	if result := Login(context.Background(), old, testPepper, Input{Email: logging.Redacted(testEmail), Password: []byte(testWrongPassword)}); result.Outcome != OutcomeUnauthorized {
+		t.Fatalf("Login() outcome = %v, want unauthorized", result.Outcome)
+	}
+
+	if current.updateCalls != 0 || old.updateCalls != 0 {
+		t.Fatalf("UpdatePasswordHash called (%d, %d times), want never", current.updateCalls, old.updateCalls)
+	}
+}
+
+// Requirement: DV-F-14
+func TestLogin_FailedUpgradeStillSucceeds(t *testing.T) {
+	emailHash := hashing.HashEmail(logging.Redacted(testEmail))
+	updateErr := errors.New("connection reset")
+	store := &fakeStorage{hashes: map[string]string{emailHash: newOldParameterHash(t)}, updateErr: updateErr}
+
+	result := Login(context.Background(), store, testPepper, Input{Email: logging.Redacted(testEmail), Password: []byte(testPassword)})
+
+	if result.Outcome != OutcomeSuccess {
+		t.Fatalf("Outcome = %v, want success despite the failed upgrade", result.Outcome)
+	}
+	if !errors.Is(result.RehashErr, ErrRehashFailed) || !errors.Is(result.RehashErr, updateErr) {
+		t.Fatalf("RehashErr = %v, want wrapping ErrRehashFailed and %v", result.RehashErr, updateErr)
+	}
+}
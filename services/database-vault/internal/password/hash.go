@@ -124,12 +124,9 @@ func composePassword(password, pepper []byte) []byte {
 // record was created with, even if this file's argonTime/argonMemoryKiB/
 // argonThreads/argonKeyLen constants are ever changed later — the alternative
 // (recomputing with "whatever the current constants say") would silently
-// break every hash created under different parameters. The SRS has no
-// stated requirement for parameter rotation/versioning (unlike DV-F-19's
-// master-key rotation, which is only a "should"), so no rotation policy is
-// implemented here — only the self-describing format itself, which is a
-// small, low-risk addition that keeps that door open without building
-// anything unrequested.
+// break every hash created under different parameters. It is also what
+// lets NeedsRehash spot a hash made under older parameters, so the login
+// package can upgrade it on the user's next successful login.
 //
 // See composePassword's doc comment for how password, salt, and pepper are
 // combined into the underlying argon2.IDKey call.
@@ -167,6 +164,23 @@ func VerifyPassword(password, pepper []byte, encoded string) (bool, error) {
 	return subtle.ConstantTimeCompare(gotHash, wantHash) == 1, nil
 }
 
+// NeedsRehash reports whether encoded, a hash VerifyPassword has just
+// matched, was produced with cost parameters or a digest length other
+// than the current argonTime/argonMemoryKiB/argonThreads/argonKeyLen - the
+// case HashPassword's self-describing format exists to keep verifiable.
+// The login package uses it to re-hash such a password with the current
+// parameters while the plaintext is at hand, so raising the parameters
+// here upgrades each user on their next successful login rather than only
+// on a password change. A hash that does not parse reports false: it
+// cannot have matched, so there is no plaintext to re-hash it with.
+func NeedsRehash(encoded string) bool {
+	_, hash, time, memoryKiB, threads, err := decodeHash(encoded)
+	if err != nil {
+		return false
+	}
+	return time != argonTime || memoryKiB != argonMemoryKiB || threads != argonThreads || len(hash) != argonKeyLen
+}
+
 // encodeHash formats salt and hash into the PHC string this package uses
 // for storage (see HashPassword's doc comment). Both segments use
 // unpadded standard base64 (RawStdEncoding), the conventional choice for
@@ -257,3 +257,37 @@ func TestVerifyPassword(t *testing.T) {
 		})
 	}
 }
+
+// Requirement: DV-F-07
+func TestNeedsRehash(t *testing.T) {
+	salt := bytes.Repeat([]byte{0x02}, saltSize)
+	current, err := HashPassword([]byte("Str0ng!Pass"), salt, []byte("pepper"))
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	withParams := func(params string) string {
+		parts := strings.Split(current, "$")
+		parts[3] = params
+		return strings.Join(parts, "$")
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+		want    bool
+	}{
+		{name: "current parameters", encoded: current, want: false},
+		{name: "fewer passes", encoded: withParams("m=47104,t=1,p=1"), want: true},
+		{name: "less memory", encoded: withParams("m=19456,t=2,p=1"), want: true},
+		{name: "different parallelism", encoded: withParams("m=47104,t=2,p=4"), want: true},
+		{name: "malformed", encoded: "not-a-hash", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsRehash(tt.encoded); got != tt.want {
+				t.Fatalf("NeedsRehash(%q) = %v, want %v", tt.encoded, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrHashingBusy means a Limiter had no free slot for a new Argon2id
+// computation within its configured wait. It carries no per-request
+// content; callers map it to HTTP 503 so the upstream caller can retry
+// later, rather than to a credential failure.
+var ErrHashingBusy = errors.New("password: too many concurrent password hashes")
+
+// Limiter bounds how many Argon2id computations (HashPassword,
+// VerifyPassword) run at once. Each one holds argonMemoryKiB (46 MiB) of
+// working memory for its whole duration, so without a bound a burst of
+// registrations or logins turns directly into a burst of memory use large
+// enough to get the process OOM-killed; with one, peak Argon2id memory is
+// at most maxConcurrent * 46 MiB.
+//
+// A caller that finds every slot taken waits up to maxWait for one to
+// free up, then gives up with ErrHashingBusy. A maxWait of zero rejects
+// immediately instead of queuing.
+//
+// A nil *Limiter imposes no limit: Acquire always succeeds and Release
+// does nothing, so a Handler built without one (every existing test)
+// behaves exactly as before.
+type Limiter struct {
+	slots   chan struct{}
+	maxWait time.Duration
+}
+
+// NewLimiter returns a Limiter allowing at most maxConcurrent Argon2id
+// computations at once, each excess caller waiting at most maxWait.
+// maxConcurrent must be positive.
+func NewLimiter(maxConcurrent int, maxWait time.Duration) (*Limiter, error) {
+	if maxConcurrent <= 0 {
+		return nil, fmt.Errorf("password: limiter concurrency must be positive, got %d", maxConcurrent)
+	}
+	if maxWait < 0 {
+		return nil, fmt.Errorf("password: limiter wait must not be negative, got %s", maxWait)
+	}
+	return &Limiter{slots: make(chan struct{}, maxConcurrent), maxWait: maxWait}, nil
+}
+
+// Acquire takes a slot, waiting up to l's maxWait (or until ctx is done,
+// whichever is first) if none is free. A nil error means the caller holds
+// a slot and must call Release exactly once when its hash completes; a
+// non-nil error always wraps ErrHashingBusy and means no slot is held.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.maxWait == 0 {
+		return ErrHashingBusy
+	}
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrHashingBusy
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrHashingBusy, ctx.Err())
+	}
+}
+
+// Release returns a slot taken by a successful Acquire.
+func (l *Limiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
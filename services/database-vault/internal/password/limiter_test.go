@@ -0,0 +1,148 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Requirement: DV-F-07
+func TestNewLimiter_RejectsInvalidConfig(t *testing.T) {
+	if _, err := NewLimiter(0, time.Second); err == nil {
+		t.Error("NewLimiter(0, 1s) error = nil, want non-nil")
+	}
+	if _, err := NewLimiter(1, -time.Second); err == nil {
+		t.Error("NewLimiter(1, -1s) error = nil, want non-nil")
+	}
+}
+
+// Requirement: DV-F-07
+func TestLimiter_BoundsConcurrency(t *testing.T) {
+	const maxConcurrent = 2
+	limiter, err := NewLimiter(maxConcurrent, 5*time.Second)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v, want nil", err)
+	}
+
+	var inFlight, peak atomic.Int64
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Acquire(context.Background()); err != nil {
+				t.Errorf("Acquire() error = %v, want nil (every caller should get a slot within maxWait)", err)
+				return
+			}
+			defer limiter.Release()
+
+			current := inFlight.Add(1)
+			for {
+				seen := peak.Load()
+				if current <= seen || peak.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			inFlight.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > maxConcurrent {
+		t.Fatalf("peak concurrent holders = %d, want at most %d", got, maxConcurrent)
+	}
+}
+
+// Requirement: DV-F-07
+func TestLimiter_Acquire_WhenFull(t *testing.T) {
+	t.Run("zero wait rejects immediately", func(t *testing.T) {
+		limiter, err := NewLimiter(1, 0)
+		if err != nil {
+			t.Fatalf("NewLimiter() error = %v, want nil", err)
+		}
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("first Acquire() error = %v, want nil", err)
+		}
+		defer limiter.Release()
+
+		if err := limiter.Acquire(context.Background()); !errors.Is(err, ErrHashingBusy) {
+			t.Fatalf("second Acquire() error = %v, want ErrHashingBusy", err)
+		}
+	})
+
+	t.Run("positive wait gives up after maxWait", func(t *testing.T) {
+		const maxWait = 50 * time.Millisecond
+		limiter, err := NewLimiter(1, maxWait)
+		if err != nil {
+			t.Fatalf("NewLimiter() error = %v, want nil", err)
+		}
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("first Acquire() error = %v, want nil", err)
+		}
+		defer limiter.Release()
+
+		start := time.Now()
+		err = limiter.Acquire(context.Background())
+		if !errors.Is(err, ErrHashingBusy) {
+			t.Fatalf("second Acquire() error = %v, want ErrHashingBusy", err)
+		}
+		if elapsed := time.Since(start); elapsed < maxWait {
+			t.Fatalf("second Acquire() returned after %v, want it to wait at least %v", elapsed, maxWait)
+		}
+	})
+
+	t.Run("positive wait succeeds once a slot is released", func(t *testing.T) {
+		limiter, err := NewLimiter(1, 5*time.Second)
+		if err != nil {
+			t.Fatalf("NewLimiter() error = %v, want nil", err)
+		}
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("first Acquire() error = %v, want nil", err)
+		}
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			limiter.Release()
+		}()
+
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("queued Acquire() error = %v, want nil", err)
+		}
+		limiter.Release()
+	})
+
+	t.Run("cancelled context stops waiting", func(t *testing.T) {
+		limiter, err := NewLimiter(1, 5*time.Second)
+		if err != nil {
+			t.Fatalf("NewLimiter() error = %v, want nil", err)
+		}
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("first Acquire() error = %v, want nil", err)
+		}
+		defer limiter.Release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = limiter.Acquire(ctx)
+		if !errors.Is(err, ErrHashingBusy) || !errors.Is(err, context.Canceled) {
+			t.Fatalf("Acquire() error = %v, want wrapping ErrHashingBusy and context.Canceled", err)
+		}
+	})
+}
+
+// Requirement: DV-F-07
+func TestLimiter_NilIsUnlimited(t *testing.T) {
+	var limiter *Limiter
+
+	for range 3 {
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("nil Limiter Acquire() error = %v, want nil", err)
+		}
+	}
+	limiter.Release()
+}
@@ -8,16 +8,19 @@ package password
 import (
 	"errors"
 	"fmt"
-	"os"
+
+	"github.com/Verryx-02/RAM-USB/pkg/secrets"
 )
 
 // pepperEnvVar is the configurable source DV-F-06 requires. Per SRS §2.6
 // ("Assumptions and dependencies"), the pepper is assumed to reside in an
-// environment variable for now; no other source (file, KMS, secrets
+// environment variable, either inline or, via pepperEnvVar+"_FILE", in a
+// mounted secret file (pkg/secrets); no other source (KMS, secrets
 // manager) is in scope until the SRS says otherwise.
 const pepperEnvVar = "RAM_USB_PASSWORD_PEPPER"
 
-// ErrPepperMissing means pepperEnvVar is unset or empty.
+// ErrPepperMissing means pepperEnvVar (and its file variant) is unset or
+// empty.
 var ErrPepperMissing = errors.New("password: pepper environment variable is not set")
 
 // LoadPepper reads and validates the password-hashing pepper from its
@@ -33,8 +36,11 @@ var ErrPepperMissing = errors.New("password: pepper environment variable is not
 // pepper returns an error instead of silently falling back to an empty
 // or default value.
 func LoadPepper() ([]byte, error) {
-	value, ok := os.LookupEnv(pepperEnvVar)
-	if !ok || value == "" {
+	value, ok, err := secrets.Lookup(pepperEnvVar)
+	if err != nil {
+		return nil, fmt.Errorf("password: %w", err)
+	}
+	if !ok {
 		return nil, fmt.Errorf("%w: %s", ErrPepperMissing, pepperEnvVar)
 	}
 
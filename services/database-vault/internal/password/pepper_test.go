@@ -2,6 +2,7 @@ package password
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -73,3 +74,21 @@ func TestLoadPepper(t *testing.T) {
 		})
 	}
 }
+
+// Requirement: DV-F-06
+func TestLoadPepper_FromFileTakesPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pepper")
+	if err := os.WriteFile(path, []byte("pepper-from-file\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	t.Setenv(pepperEnvVar, "pepper-inline")
+	t.Setenv(pepperEnvVar+"_FILE", path)
+
+	pepper, err := LoadPepper()
+	if err != nil {
+		t.Fatalf("LoadPepper() unexpected error = %v", err)
+	}
+	if string(pepper) != "pepper-from-file" {
+		t.Fatalf("LoadPepper() = %q, want the file's content without its trailing newline", pepper)
+	}
+}
@@ -57,3 +57,40 @@ func GetSSHPublicKeyByPosixUsername(ctx context.Context, db Querier, posixUserna
 
 	return sshPublicKey, nil
 }
+
+// selectEmailHashBySSHPublicKeySQL matches a stored ssh_public_key whose
+// "<type> <base64>" prefix is $1, with or without the trailing comment the
+// user registered it with (DV-F-12's UNIQUE constraint is on the full
+// line, comment included). The CTE returns no row unless exactly one user
+// matches: the same key registered twice under different comments is
+// ambiguous, and an ambiguous key must not resolve to either account.
+const selectEmailHashBySSHPublicKeySQL = `
+WITH matches AS (
+    SELECT email_hash FROM users
+    WHERE ssh_public_key = $1 OR starts_with(ssh_public_key, $1 || ' ')
+)
+SELECT email_hash FROM matches WHERE (SELECT count(*) FROM matches) = 1`
+
+// ErrSSHPublicKeyNotFound means no single user has the given ssh public
+// key: either none does, or (see selectEmailHashBySSHPublicKeySQL) more
+// than one does. Like ErrPosixUsernameNotFound it is only reachable by
+// Storage-Service, so callers may map it to a distinct HTTP 404.
+var ErrSSHPublicKeyNotFound = errors.New("storage: no single user found for this ssh public key")
+
+// GetEmailHashBySSHPublicKey retrieves the email_hash (DV-F-03) of the one
+// user whose stored ssh_public_key is sshPublicKey, which must already be
+// in "<type> <base64>" form with no comment or options. The email itself
+// is never read. A returned error wrapping ErrSSHPublicKeyNotFound means
+// no single row matched; any other error means the query itself failed.
+func GetEmailHashBySSHPublicKey(ctx context.Context, db Querier, sshPublicKey string) (string, error) {
+	var emailHash string
+
+	if err := db.QueryRow(ctx, selectEmailHashBySSHPublicKeySQL, sshPublicKey).Scan(&emailHash); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%w", ErrSSHPublicKeyNotFound)
+		}
+		return "", fmt.Errorf("storage: query email hash by ssh public key: %w", err)
+	}
+
+	return emailHash, nil
+}
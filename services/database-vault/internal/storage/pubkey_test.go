@@ -64,3 +64,57 @@ func TestGetSSHPublicKeyByPosixUsername(t *testing.T) {
 		})
 	}
 }
+
+// Requirement: ST-F-11
+func TestGetEmailHashBySSHPublicKey(t *testing.T) {
+	const wantEmailHash = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name        string
+		row         fakeRow
+		wantErr     error
+		wantErrText string
+	}{
+		{
+			name: "found",
+			row:  fakeRow{value: wantEmailHash},
+		},
+		{
+			name:    "not found or ambiguous",
+			row:     fakeRow{scanErr: pgx.ErrNoRows},
+			wantErr: ErrSSHPublicKeyNotFound,
+		},
+		{
+			name:        "query failure",
+			row:         fakeRow{scanErr: errors.New("connection reset")},
+			wantErrText: "connection reset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := fakeQuerier{row: tt.row}
+
+			got, err := GetEmailHashBySSHPublicKey(context.Background(), db, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want wrapping %v", err, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantErrText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErrText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if got != wantEmailHash {
+				t.Fatalf("emailHash = %q, want %q", got, wantEmailHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package storage adds, in this file, the one in-place update this
+// service makes to an existing user record: replacing its password_hash
+// with one computed under the current Argon2id parameters, after a
+// successful login (see login.Login).
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// updatePasswordHashSQL only replaces the hash the caller verified
+// against, so a login racing a concurrent password change (or another
+// login's upgrade) can never overwrite a newer hash with its own.
+const updatePasswordHashSQL = `UPDATE users SET password_hash = $3 WHERE email_hash = $1 AND password_hash = $2`
+
+// Execer is the minimal subset of *pgxpool.Pool that UpdatePasswordHash
+// needs: a single Exec call. Same narrow-interface pattern as Querier.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// PoolExecer adapts a *pgxpool.Pool to Execer, mirroring PoolQuerier.
+type PoolExecer struct {
+	Pool *pgxpool.Pool
+}
+
+// Exec implements Execer.
+func (e PoolExecer) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return e.Pool.Exec(ctx, sql, args...)
+}
+
+// UpdatePasswordHash replaces the stored password_hash of the user
+// identified by emailHash with newHash, provided it is still oldHash. A
+// single statement needs no transaction, same as GetPasswordHash. Zero
+// rows updated is not an error: it means the hash already changed under
+// the caller, and the newer one wins.
+func UpdatePasswordHash(ctx context.Context, db Execer, emailHash, oldHash, newHash string) error {
+	if _, err := db.Exec(ctx, updatePasswordHashSQL, emailHash, oldHash, newHash); err != nil {
+		return fmt.Errorf("storage: update password hash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeExecer is a hand-written fake implementing Execer (CONTRIBUTING.md
+// §7.5), recording the SQL and arguments of its one Exec call.
+type fakeExecer struct {
+	execErr  error
+	lastSQL  string
+	lastArgs []any
+}
+
+func (e *fakeExecer) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	e.lastSQL = sql
+	e.lastArgs = args
+	return pgconn.CommandTag{}, e.execErr
+}
+
+// Requirement: DV-F-07
+func TestUpdatePasswordHash(t *testing.T) {
+	t.Run("replaces only the verified hash", func(t *testing.T) {
+		db := &fakeExecer{}
+
+		if err := UpdatePasswordHash(context.Background(), db, "email-hash", "old-hash", "new-hash"); err != nil {
+			t.Fatalf("UpdatePasswordHash() error = %v", err)
+		}
+		if db.lastSQL != updatePasswordHashSQL {
+			t.Fatalf("SQL = %q, want updatePasswordHashSQL", db.lastSQL)
+		}
+		want := []any{"email-hash", "old-hash", "new-hash"}
+		if len(db.lastArgs) != len(want) {
+			t.Fatalf("args = %v, want %v", db.lastArgs, want)
+		}
+		for i := range want {
+			if db.lastArgs[i] != want[i] {
+				t.Fatalf("args = %v, want %v", db.lastArgs, want)
+			}
+		}
+	})
+
+	t.Run("exec error is wrapped", func(t *testing.T) {
+		execErr := errors.New("connection reset")
+
+		err := UpdatePasswordHash(context.Background(), &fakeExecer{execErr: execErr}, "email-hash", "old-hash", "new-hash")
+		if !errors.Is(err, execErr) {
+			t.Fatalf("UpdatePasswordHash() error = %v, want wrapping %v", err, execErr)
+		}
+	})
+}
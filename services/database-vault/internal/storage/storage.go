@@ -50,6 +50,16 @@ const pgUniqueViolationCode = "23505"
 // an HTTP response).
 var ErrDuplicateUser = errors.New("storage: user with this email or SSH key already exists")
 
+// ErrDatabaseUnavailable means no database connection could be obtained
+// for the transaction at all, or the database was too slow to answer it:
+// the pool was exhausted until the caller's deadline passed, a new
+// connection could not be established, or the insert itself ran past the
+// deadline. Unlike any other SaveUser error, it says nothing about the
+// request itself, so a retry may well succeed (httpapi maps it to HTTP
+// 503, not 500). A context.Canceled error is never wrapped in it: that
+// means the caller went away, not that the database did.
+var ErrDatabaseUnavailable = errors.New("storage: database unavailable")
+
 // Tx is the minimal subset of pgx.Tx that SaveUser needs. Depending on this
 // narrow interface, instead of the full pgx.Tx interface (which also
 // includes CopyFrom, SendBatch, LargeObjects, Prepare, Query, QueryRow,
@@ -111,7 +121,7 @@ type UserRecord struct {
 func SaveUser(ctx context.Context, db Beginner, record UserRecord) error {
 	tx, err := db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("storage: begin transaction: %w", err)
+		return classifyBeginError(err)
 	}
 
 	encryptedEmail, err := marshalEncryptedEmail(record.EmailEncrypted)
@@ -179,15 +189,36 @@ func DeleteUser(ctx context.Context, db Beginner, emailHash string) error {
 	return nil
 }
 
+// classifyBeginError wraps a Begin error, marking it ErrDatabaseUnavailable
+// when it came from obtaining a connection rather than from the database
+// rejecting the BEGIN. pgxpool's Acquire, which Begin calls first, returns
+// the context's own error when every pooled connection stays busy until
+// ctx's deadline, and a *pgconn.ConnectError when it must open a new
+// connection and cannot. context.Canceled is deliberately not included:
+// it means the caller went away, not that the database did.
+func classifyBeginError(err error) error {
+	var connectErr *pgconn.ConnectError
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &connectErr) {
+		return fmt.Errorf("%w: begin transaction: %w", ErrDatabaseUnavailable, err)
+	}
+	return fmt.Errorf("storage: begin transaction: %w", err)
+}
+
 // classifyInsertError wraps a raw insert error, distinguishing a
-// unique-constraint violation (ErrDuplicateUser) from any other failure, so
+// unique-constraint violation (ErrDuplicateUser) and an insert that ran
+// past ctx's deadline (ErrDatabaseUnavailable) from any other failure, so
 // a future DV-F-12 handler can tell them apart via errors.Is without this
-// package needing to know anything about HTTP status codes.
+// package needing to know anything about HTTP status codes. pgx wraps the
+// context's own error when it interrupts a query, so errors.Is sees
+// through to context.DeadlineExceeded.
 func classifyInsertError(err error) error {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolationCode {
 		return fmt.Errorf("%w: %s", ErrDuplicateUser, pgErr.ConstraintName)
 	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: insert user: %w", ErrDatabaseUnavailable, err)
+	}
 
 	return fmt.Errorf("storage: insert user: %w", err)
 }
@@ -3,10 +3,12 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/Verryx-02/RAM-USB/services/database-vault/internal/encryption"
 )
@@ -156,6 +158,49 @@ func TestSaveUser_BeginError(t *testing.T) {
 	}
 }
 
+// Requirement: DV-F-08
+func TestSaveUser_BeginErrorClassification(t *testing.T) {
+	tests := []struct {
+		name            string
+		beginErr        error
+		wantUnavailable bool
+	}{
+		{name: "pool exhausted until deadline", beginErr: context.DeadlineExceeded, wantUnavailable: true},
+		{name: "wrapped deadline", beginErr: fmt.Errorf("acquire: %w", context.DeadlineExceeded), wantUnavailable: true},
+		{name: "caller canceled", beginErr: context.Canceled, wantUnavailable: false},
+		{name: "other failure", beginErr: errors.New("begin rejected"), wantUnavailable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SaveUser(context.Background(), &fakeBeginner{beginErr: tt.beginErr}, testRecord())
+
+			if !errors.Is(err, tt.beginErr) {
+				t.Fatalf("SaveUser() error = %v, want wrapping %v", err, tt.beginErr)
+			}
+			if got := errors.Is(err, ErrDatabaseUnavailable); got != tt.wantUnavailable {
+				t.Fatalf("errors.Is(err, ErrDatabaseUnavailable) = %v, want %v (err = %v)", got, tt.wantUnavailable, err)
+			}
+		})
+	}
+}
+
+// Requirement: DV-F-08
+func TestSaveUser_UnreachableDatabaseIsUnavailable(t *testing.T) {
+	// Port 1 on loopback refuses connections, so pgxpool's Acquire fails
+	// with a real *pgconn.ConnectError without any database running.
+	pool, err := pgxpool.New(context.Background(), "postgres://user@127.0.0.1:1/db?connect_timeout=2")
+	if err != nil {
+		t.Fatalf("pgxpool.New() error = %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	err = SaveUser(context.Background(), PoolBeginner{Pool: pool}, testRecord())
+	if !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Fatalf("SaveUser() error = %v, want wrapping ErrDatabaseUnavailable", err)
+	}
+}
+
 // Requirement: DV-F-08
 func TestSaveUser_RollsBackOnInsertError(t *testing.T) {
 	insertErr := errors.New("connection reset")
@@ -216,6 +261,36 @@ func TestSaveUser_OtherPgErrorIsNotDuplicate(t *testing.T) {
 	}
 }
 
+// Requirement: DV-F-08
+func TestSaveUser_InsertContextErrorClassification(t *testing.T) {
+	tests := []struct {
+		name            string
+		execErr         error
+		wantUnavailable bool
+	}{
+		{name: "insert ran past deadline", execErr: fmt.Errorf("timeout: %w", context.DeadlineExceeded), wantUnavailable: true},
+		{name: "caller canceled", execErr: fmt.Errorf("timeout: %w", context.Canceled), wantUnavailable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := &fakeTx{execErr: tt.execErr}
+
+			err := SaveUser(context.Background(), &fakeBeginner{tx: tx}, testRecord())
+
+			if !errors.Is(err, tt.execErr) {
+				t.Fatalf("SaveUser() error = %v, want wrapping %v", err, tt.execErr)
+			}
+			if got := errors.Is(err, ErrDatabaseUnavailable); got != tt.wantUnavailable {
+				t.Fatalf("errors.Is(err, ErrDatabaseUnavailable) = %v, want %v (err = %v)", got, tt.wantUnavailable, err)
+			}
+			if !tx.rollbackCalled {
+				t.Fatal("Rollback was not called after an Exec error")
+			}
+		})
+	}
+}
+
 // Requirement: DV-F-08
 func TestSaveUser_RollbackErrorIsSurfaced(t *testing.T) {
 	insertErr := errors.New("insert failed")
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// config holds every value run reads from the environment, loaded and
+// validated once by loadConfig before anything is built from it, so a
+// misconfiguration fails startup (RD-04) with one clear error instead of
+// surfacing later as, e.g., a TLS handshake failure against a mistyped
+// Security-Switch address.
+type config struct {
+	// listenAddr is envListenAddr: host:port, host optional.
+	listenAddr string
+	// serverCertPath/serverKeyPath are envServerCert/envServerKey.
+	serverCertPath string
+	serverKeyPath  string
+	// securitySwitchURL is envSecuritySwitchURL: an https URL whose host is
+	// a hostname or IP address, with an optional port.
+	securitySwitchURL string
+	// mqttBrokerURL is envMQTTBrokerURL, empty if metrics publishing is
+	// not configured (see buildMetricsClient).
+	mqttBrokerURL string
+	// securitySwitchDialTimeout/securitySwitchHandshakeTimeout are
+	// envSecuritySwitchDialTimeout/envSecuritySwitchHandshakeTimeout.
+	securitySwitchDialTimeout      time.Duration
+	securitySwitchHandshakeTimeout time.Duration
+	// maxConnectionsPerIP is envMaxConnectionsPerIP; 0 means no cap.
+	maxConnectionsPerIP int
+	// registrationRateLimit, registrationRateWindow, and
+	// registrationRateTrustedNetworks are envRegistrationRateLimit (0
+	// means no cap), envRegistrationRateWindow, and
+	// envRegistrationRateTrustedNetworks.
+	registrationRateLimit           int
+	registrationRateWindow          time.Duration
+	registrationRateTrustedNetworks []netip.Prefix
+	// readinessCacheTTL is envReadinessCacheTTL.
+	readinessCacheTTL time.Duration
+	// requireEmailMX is envRequireEmailMX.
+	requireEmailMX bool
+}
+
+// loadConfig reads every Entry-Hub env var into a config and validates
+// it. A required value missing, or any value present but malformed, is an
+// error.
+func loadConfig() (config, error) {
+	var cfg config
+	var err error
+
+	if cfg.listenAddr, err = requireEnv(envListenAddr); err != nil {
+		return config{}, err
+	}
+	if cfg.serverCertPath, err = requireEnv(envServerCert); err != nil {
+		return config{}, err
+	}
+	if cfg.serverKeyPath, err = requireEnv(envServerKey); err != nil {
+		return config{}, err
+	}
+	if cfg.securitySwitchURL, err = requireEnv(envSecuritySwitchURL); err != nil {
+		return config{}, err
+	}
+	cfg.mqttBrokerURL = os.Getenv(envMQTTBrokerURL)
+
+	if cfg.securitySwitchDialTimeout, err = getEnvDuration(envSecuritySwitchDialTimeout, defaultSecuritySwitchDialTimeout); err != nil {
+		return config{}, err
+	}
+	if cfg.securitySwitchHandshakeTimeout, err = getEnvDuration(envSecuritySwitchHandshakeTimeout, defaultSecuritySwitchHandshakeTimeout); err != nil {
+		return config{}, err
+	}
+
+	if cfg.maxConnectionsPerIP, err = getEnvNonNegativeInt(envMaxConnectionsPerIP, 0); err != nil {
+		return config{}, err
+	}
+
+	if cfg.registrationRateLimit, err = getEnvNonNegativeInt(envRegistrationRateLimit, 0); err != nil {
+		return config{}, err
+	}
+	if cfg.registrationRateWindow, err = getEnvDuration(envRegistrationRateWindow, defaultRegistrationRateWindow); err != nil {
+		return config{}, err
+	}
+	if cfg.registrationRateTrustedNetworks, err = parseNetworks(os.Getenv(envRegistrationRateTrustedNetworks)); err != nil {
+		return config{}, fmt.Errorf("%s: %w", envRegistrationRateTrustedNetworks, err)
+	}
+
+	if cfg.readinessCacheTTL, err = getEnvDuration(envReadinessCacheTTL, defaultReadinessCacheTTL); err != nil {
+		return config{}, err
+	}
+
+	if cfg.requireEmailMX, err = getEnvBool(envRequireEmailMX); err != nil {
+		return config{}, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return config{}, err
+	}
+	return cfg, nil
+}
+
+// validate checks cfg's values for shape only - that the certificate and
+// key files exist, that every port is in range, and that
+// securitySwitchURL names an https host - not that any of them actually
+// work; loading the key pair and dialing Security-Switch still happen
+// later, in buildServerTLSConfig/buildSecuritySwitchClient.
+func (cfg config) validate() error {
+	if _, port, err := net.SplitHostPort(cfg.listenAddr); err != nil {
+		return fmt.Errorf("%s %q is not a host:port address: %w", envListenAddr, cfg.listenAddr, err)
+	} else if err := validatePort(port); err != nil {
+		return fmt.Errorf("%s: %w", envListenAddr, err)
+	}
+
+	if err := validateFile(cfg.serverCertPath); err != nil {
+		return fmt.Errorf("%s: %w", envServerCert, err)
+	}
+	if err := validateFile(cfg.serverKeyPath); err != nil {
+		return fmt.Errorf("%s: %w", envServerKey, err)
+	}
+
+	if err := validateSecuritySwitchURL(cfg.securitySwitchURL); err != nil {
+		return fmt.Errorf("%s: %w", envSecuritySwitchURL, err)
+	}
+
+	return nil
+}
+
+// getEnvBool reads name from the environment as a bool, defaulting to
+// false if unset or empty. A value present but not parseable as a bool
+// (strconv.ParseBool's accepted forms) is a startup failure (RD-04,
+// fail-secure) - not silently treated as false.
+func getEnvBool(name string) (bool, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("environment variable %s is not a valid bool: %w", name, err)
+	}
+	return parsed, nil
+}
+
+// parseNetworks parses raw as comma-separated CIDR prefixes, ignoring
+// surrounding whitespace and empty entries. An empty raw is no networks.
+func parseNetworks(raw string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		network, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("parse %q: %w", field, err)
+		}
+		networks = append(networks, network.Masked())
+	}
+	return networks, nil
+}
+
+// validatePort checks port is a decimal TCP port number in 1-65535.
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("port %q is not in 1-65535", port)
+	}
+	return nil
+}
+
+// validateFile checks path names an existing regular file.
+func validateFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+	return nil
+}
+
+// validateSecuritySwitchURL checks raw is an https URL with a well-formed
+// host and, if given, an in-range port. A host made only of digits and
+// dots must parse as an IPv4 address: "10.0.0.256" is a mistyped IP, not
+// a hostname.
+func validateSecuritySwitchURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("parse %q: %w", raw, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%q must use https (EH-F-07's mTLS)", raw)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	if port := u.Port(); port != "" {
+		if err := validatePort(port); err != nil {
+			return err
+		}
+	}
+
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if strings.Trim(host, "0123456789.") == "" {
+		return fmt.Errorf("host %q is not a valid IP address", host)
+	}
+	if !isHostname(host) {
+		return fmt.Errorf("host %q is not a valid hostname or IP address", host)
+	}
+	return nil
+}
+
+// isHostname reports whether host is a syntactically valid DNS name:
+// dot-separated labels of 1-63 letters, digits, and inner hyphens.
+func isHostname(host string) bool {
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTempFile creates an empty file in a per-test directory and returns
+// its path. validate checks existence only, so no real PEM content is
+// needed.
+func writeTempFile(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile(%s) error = %v", path, err)
+	}
+	return path
+}
+
+// Requirement: EH-F-01
+// Requirement: EH-F-07
+func TestConfigValidate(t *testing.T) {
+	certPath := writeTempFile(t, "server.crt")
+	keyPath := writeTempFile(t, "server.key")
+	missingPath := filepath.Join(t.TempDir(), "missing.crt")
+
+	valid := config{
+		listenAddr:        ":8443",
+		serverCertPath:    certPath,
+		serverKeyPath:     keyPath,
+		securitySwitchURL: "https://security-switch.internal:8443",
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*config)
+		wantErr string
+	}{
+		{name: "valid hostname", mutate: func(*config) {}},
+		{name: "valid IPv4", mutate: func(c *config) { c.securitySwitchURL = "https://10.0.0.12:8443" }},
+		{name: "valid IPv6", mutate: func(c *config) { c.securitySwitchURL = "https://[fd00::12]:8443" }},
+		{name: "URL without port", mutate: func(c *config) { c.securitySwitchURL = "https://security-switch" }},
+		{name: "missing certificate", mutate: func(c *config) { c.serverCertPath = missingPath }, wantErr: envServerCert},
+		{name: "missing key", mutate: func(c *config) { c.serverKeyPath = missingPath }, wantErr: envServerKey},
+		{name: "certificate path is a directory", mutate: func(c *config) { c.serverCertPath = t.TempDir() }, wantErr: envServerCert},
+		{name: "invalid IPv4", mutate: func(c *config) { c.securitySwitchURL = "https://10.0.0.256:8443" }, wantErr: envSecuritySwitchURL},
+		{name: "invalid hostname", mutate: func(c *config) { c.securitySwitchURL = "https://security_switch:8443" }, wantErr: envSecuritySwitchURL},
+		{name: "plain http", mutate: func(c *config) { c.securitySwitchURL = "http://security-switch:8443" }, wantErr: envSecuritySwitchURL},
+		{name: "no host", mutate: func(c *config) { c.securitySwitchURL = "https://:8443" }, wantErr: envSecuritySwitchURL},
+		{name: "Security-Switch port out of range", mutate: func(c *config) { c.securitySwitchURL = "https://security-switch:70000" }, wantErr: envSecuritySwitchURL},
+		{name: "listen port zero", mutate: func(c *config) { c.listenAddr = ":0" }, wantErr: envListenAddr},
+		{name: "listen port out of range", mutate: func(c *config) { c.listenAddr = ":65536" }, wantErr: envListenAddr},
+		{name: "listen address without port", mutate: func(c *config) { c.listenAddr = "0.0.0.0" }, wantErr: envListenAddr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("validate() error = nil, want non-nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validate() error = %q, want it to name %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Requirement: EH-F-01
+func TestLoadConfig_MissingRequiredValueFails(t *testing.T) {
+	t.Setenv(envListenAddr, ":8443")
+	t.Setenv(envServerCert, writeTempFile(t, "server.crt"))
+	t.Setenv(envServerKey, writeTempFile(t, "server.key"))
+	t.Setenv(envSecuritySwitchURL, "")
+
+	_, err := loadConfig()
+	if err == nil || !strings.Contains(err.Error(), envSecuritySwitchURL) {
+		t.Fatalf("loadConfig() error = %v, want one naming %s", err, envSecuritySwitchURL)
+	}
+}
+
+// Requirement: EH-F-01
+func TestLoadConfig_Valid(t *testing.T) {
+	t.Setenv(envListenAddr, ":8443")
+	t.Setenv(envServerCert, writeTempFile(t, "server.crt"))
+	t.Setenv(envServerKey, writeTempFile(t, "server.key"))
+	t.Setenv(envSecuritySwitchURL, "https://security-switch:8443")
+	t.Setenv(envMQTTBrokerURL, "")
+	t.Setenv(envSecuritySwitchDialTimeout, "2s")
+	t.Setenv(envSecuritySwitchHandshakeTimeout, "")
+	t.Setenv(envMaxConnectionsPerIP, "")
+	t.Setenv(envReadinessCacheTTL, "")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v, want nil", err)
+	}
+	if cfg.securitySwitchDialTimeout.String() != "2s" || cfg.securitySwitchHandshakeTimeout != defaultSecuritySwitchHandshakeTimeout {
+		t.Fatalf("timeouts = (%v, %v), want (2s, %v)", cfg.securitySwitchDialTimeout, cfg.securitySwitchHandshakeTimeout, defaultSecuritySwitchHandshakeTimeout)
+	}
+	if cfg.maxConnectionsPerIP != 0 {
+		t.Fatalf("maxConnectionsPerIP = %d, want 0 (no cap)", cfg.maxConnectionsPerIP)
+	}
+	if cfg.readinessCacheTTL != defaultReadinessCacheTTL {
+		t.Fatalf("readinessCacheTTL = %v, want %v", cfg.readinessCacheTTL, defaultReadinessCacheTTL)
+	}
+}
+
+// Requirement: EH-F-01
+func TestLoadConfig_MaxConnectionsPerIP(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "0", want: 0},
+		{value: "10", want: 10},
+		{value: "-1", wantErr: true},
+		{value: "many", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(envListenAddr, ":8443")
+			t.Setenv(envServerCert, writeTempFile(t, "server.crt"))
+			t.Setenv(envServerKey, writeTempFile(t, "server.key"))
+			t.Setenv(envSecuritySwitchURL, "https://security-switch:8443")
+			t.Setenv(envMaxConnectionsPerIP, tt.value)
+
+			cfg, err := loadConfig()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("loadConfig() error = nil, want non-nil for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig() error = %v", err)
+			}
+			if cfg.maxConnectionsPerIP != tt.want {
+				t.Fatalf("maxConnectionsPerIP = %d, want %d", cfg.maxConnectionsPerIP, tt.want)
+			}
+		})
+	}
+}
+
+// Requirement: EH-F-02
+func TestLoadConfig_RegistrationRateTrustedNetworks(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "10.0.0.0/8, 192.168.1.7/32,", want: []string{"10.0.0.0/8", "192.168.1.7/32"}},
+		{value: "10.1.2.3/8", want: []string{"10.0.0.0/8"}},
+		{value: "10.0.0.1", wantErr: true},
+		{value: "10.0.0.0/33", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(envListenAddr, ":8443")
+			t.Setenv(envServerCert, writeTempFile(t, "server.crt"))
+			t.Setenv(envServerKey, writeTempFile(t, "server.key"))
+			t.Setenv(envSecuritySwitchURL, "https://security-switch:8443")
+			t.Setenv(envRegistrationRateTrustedNetworks, tt.value)
+
+			cfg, err := loadConfig()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), envRegistrationRateTrustedNetworks) {
+					t.Fatalf("loadConfig() error = %v, want one naming %s", err, envRegistrationRateTrustedNetworks)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadConfig() error = %v", err)
+			}
+			if len(cfg.registrationRateTrustedNetworks) != len(tt.want) {
+				t.Fatalf("trusted networks = %v, want %v", cfg.registrationRateTrustedNetworks, tt.want)
+			}
+			for i, network := range cfg.registrationRateTrustedNetworks {
+				if network.String() != tt.want[i] {
+					t.Fatalf("trusted networks = %v, want %v", cfg.registrationRateTrustedNetworks, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -7,7 +7,8 @@
 //
 // Every configuration value is read from an environment variable, per
 // CONTRIBUTING.md §7's "cmd/<service>/main.go: wiring, config loading,
-// dependency construction, server start." This mirrors
+// dependency construction, server start" - all of them up front, into one
+// validated config (config.go's loadConfig). This mirrors
 // services/security-switch/cmd/security-switch/main.go's structure
 // exactly, adapted to Entry-Hub's own inbound listener (public HTTPS, no
 // client certificate requirement - see internal/server's doc comment) and
@@ -69,18 +70,22 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
+	"github.com/Verryx-02/RAM-USB/pkg/httpmethod"
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/pkg/metrics"
 	"github.com/Verryx-02/RAM-USB/pkg/mtls"
 	"github.com/Verryx-02/RAM-USB/pkg/pki"
+	"github.com/Verryx-02/RAM-USB/pkg/utils"
 	"github.com/Verryx-02/RAM-USB/services/entry-hub/internal/httpapi"
 	"github.com/Verryx-02/RAM-USB/services/entry-hub/internal/securityswitch"
 	"github.com/Verryx-02/RAM-USB/services/entry-hub/internal/server"
@@ -125,6 +130,75 @@ const (
 	// both derived from the same pki.NewClient bootstrap already performed
 	// for Security-Switch (see this file's package doc comment).
 	envMQTTBrokerURL = "RAM_USB_MQTT_BROKER_URL"
+
+	// envSecuritySwitchDialTimeout and envSecuritySwitchHandshakeTimeout
+	// bound, separately, the TCP connect and the TLS handshake of every
+	// new connection to Security-Switch (pki.ForceServerNameWithTimeouts),
+	// as Go duration strings (e.g. "3s"). Optional: default to
+	// defaultSecuritySwitchDialTimeout/defaultSecuritySwitchHandshakeTimeout.
+	// Without them, a Security-Switch that accepts TCP but never completes
+	// the handshake holds a client's request for pki's 30-second default
+	// before EH-F-09's "service unavailable" is returned.
+	envSecuritySwitchDialTimeout      = "RAM_USB_ENTRY_HUB_SECURITY_SWITCH_DIAL_TIMEOUT"
+	envSecuritySwitchHandshakeTimeout = "RAM_USB_ENTRY_HUB_SECURITY_SWITCH_TLS_HANDSHAKE_TIMEOUT"
+
+	// envMaxConnectionsPerIP caps how many connections one remote IP may
+	// hold open on the public listener at once (server.LimitListener).
+	// Optional: defaults to 0, which disables the cap, since clients
+	// behind one proxy or NAT share an address and a safe ceiling
+	// depends on the deployment; 100 is a reasonable starting point for
+	// a directly exposed listener. A negative or non-integer value fails
+	// startup (RD-04).
+	envMaxConnectionsPerIP = "RAM_USB_ENTRY_HUB_MAX_CONNECTIONS_PER_IP"
+
+	// envRegistrationRateLimit caps how many registrations and
+	// registration dry runs (/api/validate) Entry-Hub accepts,
+	// system-wide, within any envRegistrationRateWindow (a Go duration
+	// string); httpapi.RegistrationLimiter enforces it.
+	// Registrations from an address inside one of
+	// envRegistrationRateTrustedNetworks (comma-separated CIDR prefixes)
+	// are exempt. Optional: the limit defaults to 0, which disables the
+	// cap, since a sensible ceiling depends entirely on a deployment's
+	// expected signup volume. A malformed value fails startup (RD-04).
+	envRegistrationRateLimit           = "RAM_USB_ENTRY_HUB_REGISTRATION_RATE_LIMIT"
+	envRegistrationRateWindow          = "RAM_USB_ENTRY_HUB_REGISTRATION_RATE_WINDOW"
+	envRegistrationRateTrustedNetworks = "RAM_USB_ENTRY_HUB_REGISTRATION_RATE_TRUSTED_NETWORKS"
+
+	// envReadinessCacheTTL is how long httpapi.ReadinessProbe reuses one
+	// Security-Switch reachability check before /readyz probes again, as
+	// a Go duration string. Optional: defaults to
+	// defaultReadinessCacheTTL.
+	envReadinessCacheTTL = "RAM_USB_ENTRY_HUB_READINESS_CACHE_TTL"
+
+	// envRequireEmailMX, set to true, makes registration refuse an email
+	// whose domain publishes no MX record (utils.MXChecker), failing
+	// open when DNS does not answer. Optional: defaults to false; a value
+	// that is not a bool fails startup (RD-04).
+	envRequireEmailMX = "RAM_USB_ENTRY_HUB_REQUIRE_EMAIL_MX"
+)
+
+// defaultReadinessCacheTTL is envReadinessCacheTTL's fallback: short
+// enough that a load balancer polling /readyz notices an outage within a
+// few polls, long enough that polling never adds meaningful load on
+// Security-Switch.
+const defaultReadinessCacheTTL = 5 * time.Second
+
+// readinessProbeTimeout bounds one readiness check against
+// Security-Switch, on top of the client's own dial and handshake
+// timeouts.
+const readinessProbeTimeout = 5 * time.Second
+
+// defaultRegistrationRateWindow is envRegistrationRateWindow's fallback.
+const defaultRegistrationRateWindow = time.Hour
+
+// Fallbacks for envSecuritySwitchDialTimeout/
+// envSecuritySwitchHandshakeTimeout. Security-Switch is a peer on the
+// same internal network, so a healthy connect or handshake completes in
+// milliseconds; a few seconds is generous headroom while still failing
+// fast.
+const (
+	defaultSecuritySwitchDialTimeout      = 3 * time.Second
+	defaultSecuritySwitchHandshakeTimeout = 5 * time.Second
 )
 
 // serviceName is Entry-Hub's identifier in every metrics payload it
@@ -148,6 +222,8 @@ const metricsPublishInterval = time.Minute
 const connectTimeout = 10 * time.Second
 
 func main() {
+	slog.SetDefault(logging.NewLogger(os.Stderr))
+
 	if err := run(); err != nil {
 		slog.Error("entry-hub: fatal startup error", "error", logging.Sanitize(err.Error()))
 		os.Exit(1)
@@ -158,17 +234,17 @@ func run() error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	listenAddr, err := requireEnv(envListenAddr)
+	cfg, err := loadConfig()
 	if err != nil {
 		return err
 	}
 
-	serverTLSConfig, err := buildServerTLSConfig()
+	serverTLSConfig, err := buildServerTLSConfig(cfg)
 	if err != nil {
 		return fmt.Errorf("build server tls config: %w", err)
 	}
 
-	securitySwitchClient, securitySwitchURL, mqttTLSBase, err := buildSecuritySwitchClient(ctx)
+	securitySwitchClient, securitySwitchURL, mqttTLSBase, err := buildSecuritySwitchClient(ctx, cfg)
 	if err != nil {
 		return fmt.Errorf("build security-switch client: %w", err)
 	}
@@ -179,37 +255,59 @@ func run() error {
 		SecuritySwitch: httpapi.SecuritySwitchAdapter{Client: securitySwitchClient, BaseURL: securitySwitchURL},
 		Metrics:        counters,
 	}
+	if cfg.registrationRateLimit > 0 {
+		handler.RegistrationLimit = httpapi.NewRegistrationLimiter(
+			cfg.registrationRateLimit, cfg.registrationRateWindow, cfg.registrationRateTrustedNetworks)
+	}
+
+	if cfg.requireEmailMX {
+		handler.EmailMX = utils.NewMXChecker(net.DefaultResolver, utils.DefaultMXLookupTimeout, utils.DefaultMXCacheTTL)
+	}
+	handler.Readiness = httpapi.NewReadinessProbe(func(ctx context.Context) error {
+		return securityswitch.Ping(ctx, securitySwitchClient, securitySwitchURL)
+	}, cfg.readinessCacheTTL, readinessProbeTimeout)
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("POST "+httpapi.HealthPath, handler.Health)
-	mux.HandleFunc("POST "+httpapi.RegisterPath, handler.Register)
-	mux.HandleFunc("POST "+httpapi.LoginPath, handler.Login)
+	mux.HandleFunc(httpapi.HealthPath, httpmethod.Allow(handler.Health, http.MethodPost))
+	mux.HandleFunc(httpapi.ReadyPath, httpmethod.Allow(handler.Ready, http.MethodGet))
+	mux.HandleFunc(httpapi.RegisterPath, httpmethod.Allow(handler.Register, http.MethodPost))
+	mux.HandleFunc(httpapi.LoginPath, httpmethod.Allow(handler.Login, http.MethodPost))
+	mux.HandleFunc(httpapi.ValidatePath, httpmethod.Allow(handler.Validate, http.MethodPost))
 
 	httpServer := &http.Server{
-		Addr:              listenAddr,
+		Addr:              cfg.listenAddr,
 		Handler:           mux,
 		TLSConfig:         serverTLSConfig,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
-	metricsClient, err := buildMetricsClient(mqttTLSBase)
+	metricsClient, err := buildMetricsClient(cfg.mqttBrokerURL, mqttTLSBase)
 	if err != nil {
 		return fmt.Errorf("build metrics client: %w", err)
 	}
 	if metricsClient != nil {
 		defer metricsClient.Disconnect(250)
-		go metrics.Run(ctx, metricsPublishInterval, func(publishCtx context.Context) error {
+		publishAlert, err := metrics.LoadFailureAlert()
+		if err != nil {
+			return fmt.Errorf("build metrics publish alert: %w", err)
+		}
+		go metrics.Run(ctx, metricsPublishInterval, publishAlert.Wrap(func(publishCtx context.Context) error {
 			return metrics.PublishOnce(publishCtx, metricsClient, serviceName, counters.Snapshot())
-		})
+		}))
 	}
 
 	serveErr := make(chan error, 1)
 	go func() {
-		slog.Info("entry-hub: listening", "addr", logging.Sanitize(listenAddr))
+		slog.Info("entry-hub: listening", "addr", logging.Sanitize(cfg.listenAddr))
 		// TLSConfig already carries the certificate/key pair (via
-		// server.NewTLSConfig), so ListenAndServeTLS is called with empty
+		// server.NewTLSConfig), so ServeTLS is called with empty
 		// file paths per net/http's documented convention for that case.
-		serveErr <- httpServer.ListenAndServeTLS("", "")
+		listener, err := net.Listen("tcp", cfg.listenAddr)
+		if err != nil {
+			serveErr <- fmt.Errorf("listen: %w", err)
+			return
+		}
+		serveErr <- httpServer.ServeTLS(server.LimitListener(listener, cfg.maxConnectionsPerIP), "", "")
 	}()
 
 	select {
@@ -235,22 +333,50 @@ func requireEnv(name string) (string, error) {
 	return value, nil
 }
 
-// buildServerTLSConfig assembles EH-F-01/EH-F-02/EH-F-03's public TLS
-// configuration from this server's own certificate/key. Unlike every
-// other service's buildServerTLSConfig, this has no client-CA to load -
-// server.NewTLSConfig accepts any client, by requirement (see
-// internal/server's doc comment).
-func buildServerTLSConfig() (*tls.Config, error) {
-	certPath, err := requireEnv(envServerCert)
+// getEnvNonNegativeInt reads name from the environment as a decimal
+// integer, returning fallback if it is unset or empty. A value present but
+// unparseable, or negative, is a startup failure (RD-04).
+func getEnvNonNegativeInt(name string, fallback int) (int, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("environment variable %s is not a valid integer: %w", name, err)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("environment variable %s must not be negative, got %d", name, parsed)
 	}
-	keyPath, err := requireEnv(envServerKey)
+	return parsed, nil
+}
+
+// getEnvDuration reads name from the environment as a Go duration string
+// (time.ParseDuration), returning fallback if it is unset or empty. A
+// value present but unparseable, or not positive, is a startup failure
+// (RD-04, fail-secure) - not silently replaced by fallback.
+func getEnvDuration(name string, fallback time.Duration) (time.Duration, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("environment variable %s is not a valid duration: %w", name, err)
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("environment variable %s must be positive, got %s", name, parsed)
 	}
+	return parsed, nil
+}
 
-	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+// buildServerTLSConfig assembles EH-F-01/EH-F-02/EH-F-03's public TLS
+// configuration from this server's own certificate/key. Unlike every
+// other service's buildServerTLSConfig, this has no client-CA to load -
+// server.NewTLSConfig accepts any client, by requirement (see
+// internal/server's doc comment).
+func buildServerTLSConfig(cfg config) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.serverCertPath, cfg.serverKeyPath)
 	if err != nil {
 		return nil, fmt.Errorf("load server certificate/key: %w", err)
 	}
@@ -279,11 +405,8 @@ func buildServerTLSConfig() (*tls.Config, error) {
 // identity from), so it bootstraps it directly via pki.NewClient rather
 // than deriving it from a pki.NewServer call the way Database-Vault's
 // buildStorageServiceClient does.
-func buildSecuritySwitchClient(ctx context.Context) (client *http.Client, baseURL string, mqttTLSBase *tls.Config, err error) {
-	baseURL, err = requireEnv(envSecuritySwitchURL)
-	if err != nil {
-		return nil, "", nil, err
-	}
+func buildSecuritySwitchClient(ctx context.Context, cfg config) (client *http.Client, baseURL string, mqttTLSBase *tls.Config, err error) {
+	baseURL = cfg.securitySwitchURL
 
 	token, err := pki.LoadBootstrapToken()
 	if err != nil {
@@ -302,7 +425,9 @@ func buildSecuritySwitchClient(ctx context.Context) (client *http.Client, baseUR
 	// pki.ForceServerName's own doc comment for why this is required (not
 	// merely defensive) and verified safe (chain validation against the
 	// bootstrapped RootCAs, and certificate renewal, are both unaffected).
-	if err := pki.ForceServerName(client, securityswitch.OrganizationSecuritySwitch); err != nil {
+	// The same replacement dialer also carries this process's own
+	// connect/handshake bounds.
+	if err := pki.ForceServerNameWithTimeouts(client, securityswitch.OrganizationSecuritySwitch, cfg.securitySwitchDialTimeout, cfg.securitySwitchHandshakeTimeout); err != nil {
 		return nil, "", nil, fmt.Errorf("force security-switch client TLS server name: %w", err)
 	}
 
@@ -325,11 +450,11 @@ func buildSecuritySwitchClient(ctx context.Context) (client *http.Client, baseUR
 // doc comment for why one bootstrap token, reused, is correct here rather
 // than a second independent bootstrap exchange) - as the source of this
 // connection's client certificate. A nil, nil return (no error) means
-// metrics publishing is not configured (envMQTTBrokerURL unset) - this
-// process still relays registration/login traffic without it.
-func buildMetricsClient(mqttTLSBase *tls.Config) (mqtt.Client, error) {
-	brokerURL, ok := os.LookupEnv(envMQTTBrokerURL)
-	if !ok || brokerURL == "" {
+// metrics publishing is not configured (brokerURL, from envMQTTBrokerURL,
+// empty) - this process still relays registration/login traffic without
+// it.
+func buildMetricsClient(brokerURL string, mqttTLSBase *tls.Config) (mqtt.Client, error) {
+	if brokerURL == "" {
 		slog.Warn("entry-hub: metrics publishing disabled, " + envMQTTBrokerURL + " is not set")
 		return nil, nil
 	}
@@ -159,9 +159,13 @@ func TestBuildSecuritySwitchClient_RealCA_EnforcesOrganization(t *testing.T) {
 
 		clientToken := generateToken(ctx, t, caURL, container, "EntryHub-itest-client")
 		t.Setenv(pki.BootstrapTokenEnvVar, clientToken)
-		t.Setenv(envSecuritySwitchURL, strings.Replace(stub.URL, "127.0.0.1", "localhost", 1))
+		cfg := config{
+			securitySwitchURL:              strings.Replace(stub.URL, "127.0.0.1", "localhost", 1),
+			securitySwitchDialTimeout:      defaultSecuritySwitchDialTimeout,
+			securitySwitchHandshakeTimeout: defaultSecuritySwitchHandshakeTimeout,
+		}
 
-		client, baseURL, _, err := buildSecuritySwitchClient(ctx)
+		client, baseURL, _, err := buildSecuritySwitchClient(ctx, cfg)
 		if err != nil {
 			t.Fatalf("buildSecuritySwitchClient() error = %v, want nil", err)
 		}
@@ -191,9 +195,13 @@ func TestBuildSecuritySwitchClient_RealCA_EnforcesOrganization(t *testing.T) {
 
 		clientToken := generateToken(ctx, t, caURL, container, "EntryHub-itest-client2")
 		t.Setenv(pki.BootstrapTokenEnvVar, clientToken)
-		t.Setenv(envSecuritySwitchURL, strings.Replace(stub.URL, "127.0.0.1", "localhost", 1))
+		cfg := config{
+			securitySwitchURL:              strings.Replace(stub.URL, "127.0.0.1", "localhost", 1),
+			securitySwitchDialTimeout:      defaultSecuritySwitchDialTimeout,
+			securitySwitchHandshakeTimeout: defaultSecuritySwitchHandshakeTimeout,
+		}
 
-		client, baseURL, _, err := buildSecuritySwitchClient(ctx)
+		client, baseURL, _, err := buildSecuritySwitchClient(ctx, cfg)
 		if err != nil {
 			t.Fatalf("buildSecuritySwitchClient() error = %v, want nil", err)
 		}
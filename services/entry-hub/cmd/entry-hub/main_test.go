@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Requirement: EH-F-09
+func TestGetEnvDuration(t *testing.T) {
+	const name = "RAM_USB_ENTRY_HUB_TEST_DURATION"
+	const fallback = 3 * time.Second
+
+	tests := []struct {
+		name    string
+		value   string
+		set     bool
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "unset falls back", set: false, want: fallback},
+		{name: "empty falls back", value: "", set: true, want: fallback},
+		{name: "valid duration", value: "750ms", set: true, want: 750 * time.Millisecond},
+		{name: "unparseable fails closed", value: "soon", set: true, wantErr: true},
+		{name: "zero fails closed", value: "0s", set: true, wantErr: true},
+		{name: "negative fails closed", value: "-1s", set: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.set {
+				t.Setenv(name, tt.value)
+			}
+
+			got, err := getEnvDuration(name, fallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getEnvDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("getEnvDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,11 @@ type Counters struct {
 	errorCount        atomic.Int64
 	totalResponseMs   atomic.Int64
 	activeConnections atomic.Int64
+	dryRunCount       atomic.Int64
+	dryRunErrorCount  atomic.Int64
+	mxLookupCount     atomic.Int64
+	mxRejectionCount  atomic.Int64
+	mxFailureCount    atomic.Int64
 }
 
 // BeginRequest marks one request as started, incrementing the
@@ -41,6 +46,29 @@ func (c *Counters) EndRequest(duration time.Duration, isError bool) {
 	c.activeConnections.Add(-1)
 }
 
+// RecordDryRun records one completed validate-only request (see
+// Handler.Validate) and whether it failed, counted apart from
+// EndRequest's registration and login traffic.
+func (c *Counters) RecordDryRun(isError bool) {
+	c.dryRunCount.Add(1)
+	if isError {
+		c.dryRunErrorCount.Add(1)
+	}
+}
+
+// RecordMXLookup records one email-domain MX check (see
+// Handler.EmailMX): rejected if the domain has no MX record, failed if
+// DNS gave no answer and the request was let through.
+func (c *Counters) RecordMXLookup(rejected, failed bool) {
+	c.mxLookupCount.Add(1)
+	if rejected {
+		c.mxRejectionCount.Add(1)
+	}
+	if failed {
+		c.mxFailureCount.Add(1)
+	}
+}
+
 // Snapshot converts the accumulated counts into metrics.Counters
 // (EH-F-10/EH-F-11's payload input) at the moment it's called. It does not
 // reset the accumulated totals - same open reset-vs-running-total policy
@@ -58,5 +86,10 @@ func (c *Counters) Snapshot() metrics.Counters {
 		ErrorCount:            c.errorCount.Load(),
 		AverageResponseTimeMs: average,
 		ActiveConnections:     c.activeConnections.Load(),
+		DryRunCount:           c.dryRunCount.Load(),
+		DryRunErrorCount:      c.dryRunErrorCount.Load(),
+		MXLookupCount:         c.mxLookupCount.Load(),
+		MXRejectionCount:      c.mxRejectionCount.Load(),
+		MXLookupFailureCount:  c.mxFailureCount.Load(),
 	}
 }
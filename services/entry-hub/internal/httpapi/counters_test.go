@@ -60,3 +60,36 @@ func TestCounters_ActiveConnectionsTracksInFlightRequests(t *testing.T) {
 		t.Fatalf("ActiveConnections after one EndRequest = %d, want 1", got)
 	}
 }
+
+// Requirement: EH-F-10
+func TestCounters_DryRunsAreCountedSeparately(t *testing.T) {
+	c := &Counters{}
+
+	c.RecordDryRun(false)
+	c.RecordDryRun(true)
+	c.RecordDryRun(true)
+
+	got := c.Snapshot()
+
+	if got.DryRunCount != 3 || got.DryRunErrorCount != 2 {
+		t.Fatalf("dry-run counts = (%d, %d), want (3, 2)", got.DryRunCount, got.DryRunErrorCount)
+	}
+	if got.RequestCount != 0 || got.ErrorCount != 0 {
+		t.Fatalf("request counts = (%d, %d), want dry runs kept out of them", got.RequestCount, got.ErrorCount)
+	}
+}
+
+// Requirement: EH-F-10
+func TestCounters_RecordMXLookup(t *testing.T) {
+	c := &Counters{}
+
+	c.RecordMXLookup(false, false)
+	c.RecordMXLookup(true, false)
+	c.RecordMXLookup(false, true)
+
+	got := c.Snapshot()
+
+	if got.MXLookupCount != 3 || got.MXRejectionCount != 1 || got.MXLookupFailureCount != 1 {
+		t.Fatalf("mx counts = (%d, %d, %d), want (3, 1, 1)", got.MXLookupCount, got.MXRejectionCount, got.MXLookupFailureCount)
+	}
+}
@@ -68,9 +68,10 @@ func writeForwardedResponse(w http.ResponseWriter, result securityswitch.Result)
 // mapSecuritySwitchError implements EH-F-09 for a failed call to
 // Security-Switch (not a response Security-Switch itself sent, which is
 // always relayed unchanged by writeForwardedResponse instead): a timeout
-// maps to 503, any other unreachable failure to 502, falling back to 500
-// for anything else. EH-F-09's fixed status set (400/401/500/502/503)
-// deliberately differs from Security-Switch's own SS-F-06 set
+// maps to 503, any other unreachable failure - or a non-JSON response of
+// any status, which is treated as a failed call rather than relayed - to
+// 502, falling back to 500 for anything else. EH-F-09's fixed status set
+// (400/401/500/502/503) deliberately differs from Security-Switch's own SS-F-06 set
 // (400/401/403/500/502/504): Entry-Hub never constructs a 403 (it has no
 // downstream "explicit refusal" case of its own, unlike Security-Switch's
 // Network-Manager grant denial) and uses 503, not 504, for a timed-out
@@ -80,7 +81,9 @@ func mapSecuritySwitchError(err error) *apperrors.AppError {
 	switch {
 	case errors.Is(err, securityswitch.ErrSecuritySwitchTimeout):
 		return apperrors.NewServiceUnavailable(err)
-	case errors.Is(err, securityswitch.ErrSecuritySwitchUnreachable):
+	case errors.Is(err, securityswitch.ErrSecuritySwitchUnreachable),
+		errors.Is(err, securityswitch.ErrSecuritySwitchServerError),
+		errors.Is(err, securityswitch.ErrSecuritySwitchUnexpectedResponse):
 		return apperrors.NewBadGateway(err)
 	default:
 		return apperrors.NewInternal(err)
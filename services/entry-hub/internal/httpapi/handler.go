@@ -28,6 +28,7 @@ import (
 	"time"
 
 	apperrors "github.com/Verryx-02/RAM-USB/pkg/errors"
+	"github.com/Verryx-02/RAM-USB/pkg/utils"
 	"github.com/Verryx-02/RAM-USB/pkg/validation"
 )
 
@@ -35,16 +36,20 @@ import (
 // endpoint paths, literally specified by EH-F-01/EH-F-02/EH-F-03. Each is
 // a bare URL path (usable directly as an httptest.NewRequest target),
 // not a Go 1.22+ enhanced-ServeMux "METHOD pattern" string - main.go
-// combines a path with its required POST method
-// (net/http.ServeMux.HandleFunc("POST "+httpapi.RegisterPath, ...)) at
-// registration time, keeping this constant reusable as a plain path in
-// tests too.
+// enforces the required POST method at registration time
+// (httpmethod.Allow(handler.Register, http.MethodPost)), keeping this
+// constant reusable as a plain path in tests too.
 const (
 	HealthPath   = "/api/health"
 	RegisterPath = "/api/register"
 	LoginPath    = "/api/login"
 )
 
+// ValidatePath is Entry-Hub's registration dry-run endpoint (see
+// Validate). Not named by any EH-F-* requirement; this path is this
+// session's judgment call, chosen to sit beside RegisterPath.
+const ValidatePath = "/api/validate"
+
 // Handler ties Entry-Hub's public endpoints together: health (EH-F-01),
 // registration re-validation and forwarding (EH-F-02/04/06/07/08/09), and
 // login re-validation and forwarding (EH-F-03/05/06/07/08/09).
@@ -57,11 +62,29 @@ type Handler struct {
 	// Register/Login - only Health has no downstream dependency.
 	SecuritySwitch SecuritySwitchClient
 
-	// Metrics accumulates request/error/response-time counts feeding
-	// EH-F-10/EH-F-11's periodic publish. Must not be nil for
-	// Register/Login.
+	// Metrics accumulates request/error/response-time counts, and
+	// Validate's dry-run counts, feeding EH-F-10/EH-F-11's periodic
+	// publish. Must not be nil for Register/Validate/Login.
 	Metrics *Counters
 
+	// RegistrationLimit, if non-nil, caps system-wide registrations
+	// (see RegistrationLimiter). Register and Validate answer a request
+	// over the cap with EH-F-09's HTTP 503 instead of forwarding or
+	// checking it, so a flood of dry runs can no more drive unbounded
+	// EmailMX lookups than a flood of registrations can. Nil disables
+	// the cap.
+	RegistrationLimit *RegistrationLimiter
+
+	// EmailMX, if non-nil, makes Register and Validate refuse, as a
+	// validation failure, an email whose domain publishes no MX record
+	// (see utils.MXChecker). A DNS failure lets the request through. Nil
+	// disables the check.
+	EmailMX *utils.MXChecker
+
+	// Readiness, if non-nil, is the downstream check Ready reports on
+	// (see ReadinessProbe). Nil makes Ready answer like Health.
+	Readiness *ReadinessProbe
+
 	// Logger receives every structured log line this handler writes. If
 	// nil, slog.Default() is used. Tests inject a logger writing to a
 	// buffer to assert EH-F-06's "no user-identifying value in the log"
@@ -96,7 +119,8 @@ func (h *Handler) Health(w http.ResponseWriter, _ *http.Request) {
 // key, and no call to Security-Switch at all. On success, EH-F-07
 // applies: log the outcome without identifying the user, then forward to
 // Security-Switch and relay its response unchanged (EH-F-08/EH-F-09) -
-// see forward.go.
+// see forward.go - unless RegistrationLimit's system-wide cap is
+// reached, in which case it responds HTTP 503 without forwarding.
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	h.Metrics.BeginRequest()
@@ -118,11 +142,76 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.allowRegistration(w, r, "register") {
+		isError = true
+		return
+	}
+
+	if err := h.checkEmailDomain(r.Context(), "register", req.Email); err != nil {
+		isError = true
+		h.failValidation(w, "register", err)
+		return
+	}
+
 	h.logger().Info("register: validation succeeded, forwarding to security-switch")
 
 	h.forwardRegister(w, r, req, &isError)
 }
 
+// validateResponse is the JSON body Validate writes when every field
+// passes.
+type validateResponse struct {
+	Status string `json:"status"`
+}
+
+// Validate runs exactly Register's decode and validation steps (EH-F-02,
+// EH-F-04) against a registration body and reports the result, without
+// forwarding anything to Security-Switch or committing a registration -
+// so a client can check a user's input before submitting it for real. A
+// failure is reported exactly as Register reports one (EH-F-06: HTTP
+// 400, the same generic body, the same log line without the offending
+// values), so this endpoint reveals nothing about why input is invalid
+// that Register itself does not.
+//
+// Counted in h.Metrics only as a dry run (Counters.RecordDryRun), never
+// in EH-F-10's request/error counts: those describe registration and
+// login traffic, and a client validating as its user types would
+// otherwise inflate both by an order of magnitude. A dry run does count
+// against RegistrationLimit, exactly like a registration, since it costs
+// the same EmailMX lookup.
+func (h *Handler) Validate(w http.ResponseWriter, r *http.Request) {
+	isError := false
+	defer func() {
+		h.Metrics.RecordDryRun(isError)
+	}()
+
+	req, err := validation.DecodeRegisterRequest(r.Body)
+	if err != nil {
+		isError = true
+		h.failValidation(w, "validate", err)
+		return
+	}
+
+	if err := validation.ValidateRegister(req); err != nil {
+		isError = true
+		h.failValidation(w, "validate", err)
+		return
+	}
+
+	if !h.allowRegistration(w, r, "validate") {
+		isError = true
+		return
+	}
+
+	if err := h.checkEmailDomain(r.Context(), "validate", req.Email); err != nil {
+		isError = true
+		h.failValidation(w, "validate", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, validateResponse{Status: "valid"})
+}
+
 // Login handles a login request from a client (CL-F-03): decode and
 // validate (EH-F-03, EH-F-05). On failure, EH-F-06 applies identically to
 // Register. On success, EH-F-07 forwards to Security-Switch and relays
@@ -153,6 +242,27 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	h.forwardLogin(w, r, req, &isError)
 }
 
+// allowRegistration reports whether h.RegistrationLimit lets r through.
+// If not, it has already answered with EH-F-09's HTTP 503, logging an
+// alert on the first refusal of an episode and a warning after that.
+func (h *Handler) allowRegistration(w http.ResponseWriter, r *http.Request, endpoint string) bool {
+	if h.RegistrationLimit == nil {
+		return true
+	}
+	allowed, tripped := h.RegistrationLimit.Allow(r.RemoteAddr, time.Now())
+	if allowed {
+		return true
+	}
+	if tripped {
+		h.logger().Error(endpoint+": system-wide registration rate exceeded, refusing registrations",
+			"alert", RegistrationRateAlertKey)
+	} else {
+		h.logger().Warn(endpoint + ": refused, system-wide registration rate exceeded")
+	}
+	writeAppError(w, apperrors.NewServiceUnavailable(errRegistrationRateExceeded))
+	return false
+}
+
 // failValidation implements EH-F-06 for both handlers: respond HTTP 400
 // with a generic body, and log the failure without the email, password,
 // or SSH key. err is always one of pkg/validation's sentinel errors, none
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Verryx-02/RAM-USB/pkg/validation"
 	"github.com/Verryx-02/RAM-USB/services/entry-hub/internal/httpapi"
@@ -128,6 +130,42 @@ func TestHandler_Register_SuccessRelaysResponseUnchanged(t *testing.T) {
 	}
 }
 
+// Requirement: EH-F-09
+func TestHandler_Register_GlobalRateLimitReturnsServiceUnavailable(t *testing.T) {
+	securitySwitch := &fakeSecuritySwitch{
+		registerResult: securityswitch.Result{StatusCode: http.StatusCreated, ContentType: "application/json", Body: []byte(`{}`)},
+	}
+	h, logBuf := newTestHandler(securitySwitch)
+	h.RegistrationLimit = httpapi.NewRegistrationLimiter(2, time.Hour, nil)
+
+	register := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, httpapi.RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.Register(rec, req)
+		return rec
+	}
+
+	// Distinct addresses: the cap is system-wide, not per client.
+	for _, addr := range []string{"198.51.100.1:1000", "198.51.100.2:1000"} {
+		if rec := register(addr); rec.Code != http.StatusCreated {
+			t.Fatalf("registration from %s status = %d, want %d", addr, rec.Code, http.StatusCreated)
+		}
+	}
+
+	securitySwitch.registerCalled = false
+	rec := register("198.51.100.3:1000")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status over the cap = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if securitySwitch.registerCalled {
+		t.Fatal("a registration over the cap was forwarded to Security-Switch")
+	}
+	if !strings.Contains(logBuf.String(), httpapi.RegistrationRateAlertKey) {
+		t.Fatalf("log lacks alert=%s:\n%s", httpapi.RegistrationRateAlertKey, logBuf.String())
+	}
+}
+
 // Requirement: EH-F-08
 func TestHandler_Register_DuplicateRelayedUnchanged(t *testing.T) {
 	securitySwitch := &fakeSecuritySwitch{
@@ -193,6 +231,43 @@ func TestHandler_Register_SecuritySwitchUnreachableMapsToBadGateway(t *testing.T
 	}
 }
 
+// Requirement: EH-F-09
+func TestHandler_Register_SecuritySwitchServerErrorMapsToBadGateway(t *testing.T) {
+	securitySwitch := &fakeSecuritySwitch{
+		registerResult: securityswitch.Result{Err: securityswitch.ErrSecuritySwitchServerError},
+	}
+	h, _ := newTestHandler(securitySwitch)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, httpapi.RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+}
+
+// Requirement: EH-F-09
+func TestHandler_Register_SecuritySwitchUnexpectedResponseMapsToBadGateway(t *testing.T) {
+	securitySwitch := &fakeSecuritySwitch{
+		registerResult: securityswitch.Result{Err: fmt.Errorf("%w: status 200", securityswitch.ErrSecuritySwitchUnexpectedResponse)},
+	}
+	h, logBuf := newTestHandler(securitySwitch)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, httpapi.RegisterPath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+	if !strings.Contains(logBuf.String(), "unexpected response: status 200") {
+		t.Fatalf("log lacks the upstream status:\n%s", logBuf.String())
+	}
+}
+
 // Requirement: EH-F-09
 func TestHandler_Register_SecuritySwitchTimeoutMapsToServiceUnavailable(t *testing.T) {
 	securitySwitch := &fakeSecuritySwitch{
@@ -223,6 +298,7 @@ func TestHandler_Register_ValidationFailure(t *testing.T) {
 		{"weak password", registerRequestBody(testEmail, "weak", testSSHPublicKey)},
 		{"malformed ssh key", registerRequestBody(testEmail, testPassword, "not-an-ssh-key")},
 		{"malformed json", `{"email":`},
+		{"deeply nested json", `{"email":` + strings.Repeat("[", 500) + strings.Repeat("]", 500) + `}`},
 	}
 
 	for _, tc := range cases {
@@ -269,6 +345,102 @@ func TestHandler_Register_ValidationFailure(t *testing.T) {
 	}
 }
 
+// Requirement: EH-F-04
+func TestHandler_Validate_ValidInputIsNotForwarded(t *testing.T) {
+	securitySwitch := &fakeSecuritySwitch{}
+	h, _ := newTestHandler(securitySwitch)
+
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, httpapi.ValidatePath, strings.NewReader(registerRequestBody(testEmail, testPassword, testSSHPublicKey)))
+	rec := httptest.NewRecorder()
+
+	h.Validate(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if securitySwitch.registerCalled || securitySwitch.loginCalled {
+		t.Fatal("Validate must never call Security-Switch")
+	}
+	if got := h.Metrics.Snapshot(); got.RequestCount != 0 {
+		t.Fatalf("RequestCount = %d, want 0 (dry runs are not counted as registration traffic)", got.RequestCount)
+	}
+	if got := h.Metrics.Snapshot(); got.DryRunCount != 1 || got.DryRunErrorCount != 0 {
+		t.Fatalf("dry-run counts = (%d, %d), want (1, 0)", got.DryRunCount, got.DryRunErrorCount)
+	}
+}
+
+// Requirement: EH-F-09
+func TestHandler_Validate_SharesTheRegistrationRateLimit(t *testing.T) {
+	securitySwitch := &fakeSecuritySwitch{
+		registerResult: securityswitch.Result{StatusCode: http.StatusCreated, ContentType: "application/json", Body: []byte(`{}`)},
+	}
+	h, _ := newTestHandler(securitySwitch)
+	h.RegistrationLimit = httpapi.NewRegistrationLimiter(2, time.Hour, nil)
+	body := registerRequestBody(testEmail, testPassword, testSSHPublicKey)
+
+	validate := func() int {
+		rec := httptest.NewRecorder()
+		h.Validate(rec, httptest.NewRequestWithContext(context.Background(), http.MethodPost, httpapi.ValidatePath, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	if code := validate(); code != http.StatusOK {
+		t.Fatalf("first dry run status = %d, want %d", code, http.StatusOK)
+	}
+	rec := httptest.NewRecorder()
+	h.Register(rec, httptest.NewRequestWithContext(context.Background(), http.MethodPost, httpapi.RegisterPath, strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("registration status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if code := validate(); code != http.StatusServiceUnavailable {
+		t.Fatalf("dry run over the cap status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+
+	if got := h.Metrics.Snapshot(); got.DryRunCount != 2 || got.DryRunErrorCount != 1 {
+		t.Fatalf("dry-run counts = (%d, %d), want (2, 1)", got.DryRunCount, got.DryRunErrorCount)
+	}
+}
+
+// Requirement: EH-F-04
+// Requirement: EH-F-06
+func TestHandler_Validate_InvalidInputIsRejectedLikeRegister(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"malformed email", registerRequestBody("not-an-email", testPassword, testSSHPublicKey)},
+		{"weak password", registerRequestBody(testEmail, "weak", testSSHPublicKey)},
+		{"malformed json", `{"email":`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			securitySwitch := &fakeSecuritySwitch{}
+			h, logBuf := newTestHandler(securitySwitch)
+
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, httpapi.ValidatePath, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			h.Validate(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if securitySwitch.registerCalled {
+				t.Fatal("Validate must never call Security-Switch")
+			}
+			if got := h.Metrics.Snapshot(); got.DryRunErrorCount != 1 || got.ErrorCount != 0 {
+				t.Fatalf("(DryRunErrorCount, ErrorCount) = (%d, %d), want (1, 0)", got.DryRunErrorCount, got.ErrorCount)
+			}
+			for _, secret := range []string{testPassword, "not-an-email", "weak"} {
+				if strings.Contains(logBuf.String(), secret) {
+					t.Fatalf("log must not identify the user, but contains %q", secret)
+				}
+			}
+		})
+	}
+}
+
 // Requirement: EH-F-07
 // Requirement: EH-F-08
 func TestHandler_Login_SuccessRelaysResponseUnchanged(t *testing.T) {
@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// errEmailDomainNoMX is the internal error behind every registration
+// refused because its email domain has no MX record, for logging only. It
+// carries neither the address nor the domain.
+var errEmailDomainNoMX = errors.New("httpapi: email domain has no mx record")
+
+// checkEmailDomain returns errEmailDomainNoMX if h.EmailMX is set and
+// email's domain has no MX record, recording the check's outcome in
+// h.Metrics. A failed lookup is logged and allowed.
+func (h *Handler) checkEmailDomain(ctx context.Context, endpoint, email string) error {
+	if h.EmailMX == nil {
+		return nil
+	}
+	at := strings.LastIndexByte(email, '@')
+	if at < 0 {
+		return nil // unreachable after validation.ValidateRegister
+	}
+	hasMX, err := h.EmailMX.HasMX(ctx, email[at+1:], time.Now())
+	if err != nil {
+		h.Metrics.RecordMXLookup(false, true)
+		// Not the error itself: a *net.DNSError names the domain, and
+		// EH-F-06 keeps every part of the email out of the log.
+		h.logger().Warn("email domain mx lookup failed, allowing", "endpoint", endpoint)
+		return nil
+	}
+	h.Metrics.RecordMXLookup(!hasMX, false)
+	if !hasMX {
+		return errEmailDomainNoMX
+	}
+	return nil
+}
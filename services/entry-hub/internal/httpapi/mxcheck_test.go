@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/utils"
+)
+
+// fakeMXResolver is a hand-written fake of utils.MXResolver
+// (CONTRIBUTING.md §7.5): it answers from records, returns NXDOMAIN for
+// any other domain, or fails every lookup with err.
+type fakeMXResolver struct {
+	records map[string][]*net.MX
+	err     error
+}
+
+func (f *fakeMXResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if records, ok := f.records[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func newFakeMXResolver() *fakeMXResolver {
+	return &fakeMXResolver{records: map[string][]*net.MX{
+		"example.com.":    {{Host: "mx1.example.com.", Pref: 10}},
+		"nullmx.example.": {{Host: ".", Pref: 0}},
+	}}
+}
+
+// Requirement: EH-F-04
+// Requirement: EH-F-06
+func TestHandler_EmailMX(t *testing.T) {
+	body := func(email string) string {
+		return `{"email":"` + email + `","password":"Str0ng!Pass","ssh_public_key":"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJl6r+SEQfM50WkfR/4iZpu9NDXCBs4RwIKidjhOCbdw"}`
+	}
+
+	tests := []struct {
+		name       string
+		handle     func(h *Handler) http.HandlerFunc
+		path       string
+		email      string
+		dnsErr     error
+		wantStatus int
+		wantMX     [3]int64 // lookups, rejections, failures
+	}{
+		{name: "register refuses a domain without mx", handle: func(h *Handler) http.HandlerFunc { return h.Register },
+			path: RegisterPath, email: "user@no-mx.example", wantStatus: http.StatusBadRequest, wantMX: [3]int64{1, 1, 0}},
+		{name: "validate refuses a domain without mx", handle: func(h *Handler) http.HandlerFunc { return h.Validate },
+			path: ValidatePath, email: "user@no-mx.example", wantStatus: http.StatusBadRequest, wantMX: [3]int64{1, 1, 0}},
+		{name: "validate accepts a domain with mx", handle: func(h *Handler) http.HandlerFunc { return h.Validate },
+			path: ValidatePath, email: "user@example.com", wantStatus: http.StatusOK, wantMX: [3]int64{1, 0, 0}},
+		{name: "dns failure fails open", handle: func(h *Handler) http.HandlerFunc { return h.Validate },
+			path: ValidatePath, email: "user@no-mx.example", dnsErr: errors.New("resolver unreachable"), wantStatus: http.StatusOK, wantMX: [3]int64{1, 0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newFakeMXResolver()
+			resolver.err = tt.dnsErr
+			var logBuf bytes.Buffer
+			h := &Handler{
+				Metrics: &Counters{},
+				EmailMX: utils.NewMXChecker(resolver, time.Second, time.Hour),
+				Logger:  slog.New(slog.NewTextHandler(&logBuf, nil)),
+			}
+
+			rec := httptest.NewRecorder()
+			tt.handle(h)(rec, httptest.NewRequestWithContext(context.Background(), http.MethodPost, tt.path, strings.NewReader(body(tt.email))))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body = %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			snapshot := h.Metrics.Snapshot()
+			if got := [3]int64{snapshot.MXLookupCount, snapshot.MXRejectionCount, snapshot.MXLookupFailureCount}; got != tt.wantMX {
+				t.Fatalf("mx counts (lookups, rejections, failures) = %v, want %v", got, tt.wantMX)
+			}
+			if strings.Contains(logBuf.String(), "example") {
+				t.Fatalf("log contains the email domain: %s", logBuf.String())
+			}
+		})
+	}
+}
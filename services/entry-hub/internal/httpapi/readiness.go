@@ -0,0 +1,82 @@
+package httpapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/logging"
+)
+
+// ReadyPath is Entry-Hub's readiness endpoint (see Ready). Unlike
+// HealthPath's liveness answer, which EH-F-01 requires to be
+// unconditional, readiness also covers Entry-Hub's one downstream
+// dependency, so a load balancer can stop routing registrations to an
+// instance that cannot reach Security-Switch without restarting it.
+const ReadyPath = "/readyz"
+
+// ReadinessProbe caches the result of a downstream check for a TTL, so
+// however often Ready is polled the downstream is probed at most once
+// per TTL. Concurrent callers arriving while a probe is in flight wait
+// for its result rather than starting their own.
+type ReadinessProbe struct {
+	check   func(ctx context.Context) error
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu        sync.Mutex
+	checked   bool
+	checkedAt time.Time
+	err       error
+}
+
+// NewReadinessProbe returns a ReadinessProbe calling check, bounded by
+// timeout, whenever its cached result is older than ttl.
+func NewReadinessProbe(check func(ctx context.Context) error, ttl, timeout time.Duration) *ReadinessProbe {
+	return &ReadinessProbe{check: check, ttl: ttl, timeout: timeout}
+}
+
+// Ready returns the cached check result as of now, probing again first if
+// there is none or it has expired. The probe is detached from ctx's
+// cancellation, so one impatient caller giving up cannot cache a failure
+// every other caller then sees.
+func (p *ReadinessProbe) Ready(ctx context.Context, now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.checked && now.Sub(p.checkedAt) < p.ttl {
+		return p.err
+	}
+
+	probeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.timeout)
+	defer cancel()
+	err := p.check(probeCtx)
+
+	switch {
+	case err != nil && (!p.checked || p.err == nil):
+		slog.Warn("entry-hub: not ready, downstream check failing", "error", logging.Sanitize(err.Error()))
+	case err == nil && p.checked && p.err != nil:
+		slog.Info("entry-hub: ready again, downstream check passing")
+	}
+
+	p.checked = true
+	p.checkedAt = now
+	p.err = err
+	return err
+}
+
+// Ready responds 200 OK if Readiness's downstream check passes, and 503
+// Service Unavailable if it does not. With no Readiness configured it
+// answers like Health. The body never carries the check's error, which
+// may name internal hosts.
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.Readiness != nil {
+		if err := h.Readiness.Ready(r.Context(), time.Now()); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, healthResponse{Status: "unavailable"})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ready"})
+}
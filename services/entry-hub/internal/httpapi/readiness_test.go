@@ -0,0 +1,102 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeDownstream is a hand-written fake downstream check
+// (CONTRIBUTING.md §7.5), counting how often it is probed.
+type fakeDownstream struct {
+	err   error
+	calls int
+}
+
+func (f *fakeDownstream) check(context.Context) error {
+	f.calls++
+	return f.err
+}
+
+// Requirement: EH-F-09
+func TestReadinessProbe_CachesForTTL(t *testing.T) {
+	start := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	downstream := &fakeDownstream{}
+	probe := NewReadinessProbe(downstream.check, 10*time.Second, time.Second)
+
+	if err := probe.Ready(context.Background(), start); err != nil {
+		t.Fatalf("Ready() error = %v with a reachable downstream", err)
+	}
+
+	// The downstream goes away, but the cached result holds until the TTL
+	// expires.
+	downstream.err = errors.New("connection refused")
+	if err := probe.Ready(context.Background(), start.Add(9*time.Second)); err != nil {
+		t.Fatalf("Ready() within TTL error = %v, want the cached nil", err)
+	}
+	if downstream.calls != 1 {
+		t.Fatalf("downstream probed %d times within TTL, want 1", downstream.calls)
+	}
+
+	if err := probe.Ready(context.Background(), start.Add(10*time.Second)); err == nil {
+		t.Fatal("Ready() after TTL error = nil with an unreachable downstream")
+	}
+
+	// Recovery is seen on the next probe after the failure's TTL.
+	downstream.err = nil
+	if err := probe.Ready(context.Background(), start.Add(15*time.Second)); err == nil {
+		t.Fatal("Ready() within TTL of a failure error = nil, want the cached failure")
+	}
+	if err := probe.Ready(context.Background(), start.Add(20*time.Second)); err != nil {
+		t.Fatalf("Ready() after recovery error = %v", err)
+	}
+	if downstream.calls != 3 {
+		t.Fatalf("downstream probed %d times, want 3", downstream.calls)
+	}
+}
+
+// Requirement: EH-F-09
+func TestReadinessProbe_IgnoresCallerCancellation(t *testing.T) {
+	probe := NewReadinessProbe(func(ctx context.Context) error { return ctx.Err() }, time.Minute, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := probe.Ready(ctx, time.Now()); err != nil {
+		t.Fatalf("Ready() with a cancelled caller error = %v, want the probe to run uncancelled", err)
+	}
+}
+
+// Requirement: EH-F-01
+// Requirement: EH-F-09
+func TestHandler_Ready(t *testing.T) {
+	tests := []struct {
+		name       string
+		downstream error
+		noProbe    bool
+		wantStatus int
+	}{
+		{name: "downstream reachable", wantStatus: http.StatusOK},
+		{name: "downstream unreachable", downstream: errors.New("connection refused"), wantStatus: http.StatusServiceUnavailable},
+		{name: "no probe configured", noProbe: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{}
+			if !tt.noProbe {
+				downstream := &fakeDownstream{err: tt.downstream}
+				h.Readiness = NewReadinessProbe(downstream.check, time.Minute, time.Second)
+			}
+
+			rec := httptest.NewRecorder()
+			h.Ready(rec, httptest.NewRequestWithContext(context.Background(), http.MethodGet, ReadyPath, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
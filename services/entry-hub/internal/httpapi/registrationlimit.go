@@ -0,0 +1,100 @@
+package httpapi
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// RegistrationRateAlertKey is the "alert" attribute value on the log line
+// Register writes when RegistrationLimiter starts refusing registrations,
+// so log-based alerting can match a likely mass-signup attempt without
+// parsing the message, same convention as pkg/metrics.FailureAlertKey.
+const RegistrationRateAlertKey = "registration_rate_exceeded"
+
+// errRegistrationRateExceeded is the internal error behind every
+// registration Register refuses because the global cap is reached.
+var errRegistrationRateExceeded = errors.New("httpapi: system-wide registration rate exceeded")
+
+// RegistrationLimiter caps how many registrations Entry-Hub forwards to
+// Security-Switch, and registration dry runs it checks, within any
+// sliding window, across every client at once.
+// It complements server.LimitListener's per-IP connection cap: a
+// mass-signup run spread over many addresses stays under any per-IP limit
+// but not under this one. Registrations from a TrustedNetworks address
+// (an operator's own provisioning host, say) are neither limited nor
+// counted.
+type RegistrationLimiter struct {
+	limit           int
+	window          time.Duration
+	trustedNetworks []netip.Prefix
+
+	mu       sync.Mutex
+	accepted []time.Time // oldest first, at most limit entries
+	limited  bool
+}
+
+// NewRegistrationLimiter returns a RegistrationLimiter allowing at most
+// limit registrations in any window-long interval. limit must be positive.
+func NewRegistrationLimiter(limit int, window time.Duration, trustedNetworks []netip.Prefix) *RegistrationLimiter {
+	return &RegistrationLimiter{
+		limit:           limit,
+		window:          window,
+		trustedNetworks: trustedNetworks,
+		accepted:        make([]time.Time, 0, limit),
+	}
+}
+
+// Allow reports whether a registration from remoteAddr (an
+// http.Request.RemoteAddr) arriving at now may go ahead, counting it if
+// so. tripped is true only for the first refusal after an accepted
+// registration, so the caller can raise one alert per episode rather
+// than one per refused request.
+func (l *RegistrationLimiter) Allow(remoteAddr string, now time.Time) (allowed, tripped bool) {
+	if l.trusted(remoteAddr) {
+		return true, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-l.window)
+	expired := 0
+	for expired < len(l.accepted) && !l.accepted[expired].After(cutoff) {
+		expired++
+	}
+	l.accepted = append(l.accepted[:0], l.accepted[expired:]...)
+
+	if len(l.accepted) >= l.limit {
+		tripped = !l.limited
+		l.limited = true
+		return false, tripped
+	}
+	l.accepted = append(l.accepted, now)
+	l.limited = false
+	return true, false
+}
+
+// trusted reports whether remoteAddr's IP is in one of l.trustedNetworks.
+func (l *RegistrationLimiter) trusted(remoteAddr string) bool {
+	if len(l.trustedNetworks) == 0 {
+		return false
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, network := range l.trustedNetworks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// Requirement: EH-F-02
+func TestRegistrationLimiter_SlidingWindow(t *testing.T) {
+	start := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	l := NewRegistrationLimiter(2, time.Minute, nil)
+
+	steps := []struct {
+		at          time.Duration
+		wantAllowed bool
+		wantTripped bool
+	}{
+		{at: 0, wantAllowed: true},
+		{at: 10 * time.Second, wantAllowed: true},
+		{at: 20 * time.Second, wantAllowed: false, wantTripped: true},
+		{at: 30 * time.Second, wantAllowed: false},                    // still limited: alert raised only once
+		{at: 60 * time.Second, wantAllowed: true},                     // the 0s registration has left the window
+		{at: 65 * time.Second, wantAllowed: false, wantTripped: true}, // a new episode alerts again
+	}
+	for _, step := range steps {
+		allowed, tripped := l.Allow("203.0.113.5:40000", start.Add(step.at))
+		if allowed != step.wantAllowed || tripped != step.wantTripped {
+			t.Fatalf("Allow() at +%v = (%v, %v), want (%v, %v)", step.at, allowed, tripped, step.wantAllowed, step.wantTripped)
+		}
+	}
+}
+
+// Requirement: EH-F-02
+func TestRegistrationLimiter_TrustedNetworksAreExemptAndUncounted(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	l := NewRegistrationLimiter(1, time.Minute, trusted)
+
+	for i := 0; i < 5; i++ {
+		if allowed, _ := l.Allow("10.1.2.3:5000", now); !allowed {
+			t.Fatalf("trusted registration #%d refused", i)
+		}
+	}
+	if allowed, _ := l.Allow("[::ffff:10.9.9.9]:5000", now); !allowed {
+		t.Fatal("IPv4-mapped trusted address refused")
+	}
+
+	if allowed, _ := l.Allow("198.51.100.1:5000", now); !allowed {
+		t.Fatal("first untrusted registration refused; trusted ones must not count")
+	}
+	if allowed, _ := l.Allow("198.51.100.2:5000", now); allowed {
+		t.Fatal("second untrusted registration allowed beyond a limit of 1")
+	}
+	if allowed, _ := l.Allow("10.1.2.3:5000", now); !allowed {
+		t.Fatal("trusted registration refused while the cap is reached")
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 
 	"github.com/Verryx-02/RAM-USB/pkg/validation"
@@ -87,6 +88,20 @@ var (
 	// ErrSecuritySwitchTimeout means the call did not complete before
 	// its context deadline elapsed.
 	ErrSecuritySwitchTimeout = errors.New("securityswitch: timed out waiting for response")
+	// ErrSecuritySwitchServerError means the peer answered with a 5xx
+	// status whose body or Content-Type is not JSON. Every error
+	// response Security-Switch's own httpapi package writes (including
+	// its deliberate SS-F-06 502/504) is a JSON object labelled
+	// application/json and is relayed unchanged; any other 5xx therefore
+	// did not come from that code (a panic-recovery page, a proxy in
+	// between, ...) and its body is not safe to relay to a client as-is.
+	ErrSecuritySwitchServerError = errors.New("securityswitch: server error with a non-JSON response")
+	// ErrSecuritySwitchUnexpectedResponse is ErrSecuritySwitchServerError's
+	// counterpart for any other status: an empty or non-JSON body, or a
+	// Content-Type other than application/json. Security-Switch's httpapi
+	// package writes neither, so, like a non-JSON 5xx, such a response
+	// came from something else in the path and is not relayed.
+	ErrSecuritySwitchUnexpectedResponse = errors.New("securityswitch: upstream returned an unexpected response")
 )
 
 // Register forwards req to Security-Switch's RegisterPath over client
@@ -103,11 +118,43 @@ func Login(ctx context.Context, client *http.Client, baseURL string, req validat
 	return forward(ctx, client, baseURL+LoginPath, req)
 }
 
+// Ping reports whether Security-Switch is reachable over client: it sends
+// a bodyless GET to baseURL and counts any complete HTTP response, whatever
+// its status, as reachable. Security-Switch exposes no health endpoint,
+// but a response means the mTLS handshake, including the
+// OrganizationSecuritySwitch check, succeeded and its server is serving.
+// Errors are classified like forward's: ErrSecuritySwitchTimeout for a
+// context deadline, ErrSecuritySwitchUnreachable otherwise.
+func Ping(ctx context.Context, client *http.Client, baseURL string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/", nil)
+	if err != nil {
+		return fmt.Errorf("securityswitch: build request: %w", err)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %w", ErrSecuritySwitchTimeout, err)
+		}
+		return fmt.Errorf("%w: %w", ErrSecuritySwitchUnreachable, err)
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, pingDrainLimit))
+	_ = resp.Body.Close()
+	return nil
+}
+
+// pingDrainLimit bounds how much of Ping's response body is read so the
+// connection can be reused; Security-Switch's 404/405 bodies are tiny.
+const pingDrainLimit = 4 << 10
+
 // forward marshals body as JSON, POSTs it to url over client, and returns
 // the peer's response completely unchanged. Any failure short of
 // receiving a complete HTTP response is reported as
 // ErrSecuritySwitchUnreachable, or ErrSecuritySwitchTimeout if the
-// failure was a context deadline.
+// failure was a context deadline; a complete 5xx response whose body or
+// Content-Type is not JSON is reported as ErrSecuritySwitchServerError,
+// and any other such response as ErrSecuritySwitchUnexpectedResponse,
+// instead of relayed.
 func forward(ctx context.Context, client *http.Client, url string, body any) Result {
 	encoded, err := json.Marshal(body)
 	if err != nil {
@@ -134,9 +181,28 @@ func forward(ctx context.Context, client *http.Client, url string, body any) Res
 		return Result{Err: fmt.Errorf("%w: read response: %w", ErrSecuritySwitchUnreachable, err)}
 	}
 
+	if resp.StatusCode >= http.StatusInternalServerError {
+		if !json.Valid(respBody) || !isJSONContentType(resp.Header.Get("Content-Type")) {
+			return Result{Err: fmt.Errorf("%w: status %d", ErrSecuritySwitchServerError, resp.StatusCode)}
+		}
+	} else if !json.Valid(respBody) || !isJSONContentType(resp.Header.Get("Content-Type")) {
+		return Result{Err: fmt.Errorf("%w: status %d", ErrSecuritySwitchUnexpectedResponse, resp.StatusCode)}
+	}
+
 	return Result{
 		StatusCode:  resp.StatusCode,
 		ContentType: resp.Header.Get("Content-Type"),
 		Body:        respBody,
 	}
 }
+
+// isJSONContentType reports whether contentType is application/json, with
+// or without parameters, or absent: writeForwardedResponse already treats
+// a missing Content-Type as JSON.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
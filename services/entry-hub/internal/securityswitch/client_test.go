@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -143,6 +145,108 @@ func TestRegister_Unreachable(t *testing.T) {
 	}
 }
 
+// Requirement: EH-F-08
+// Requirement: EH-F-09
+func TestRegister_ServerErrorResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantErr     error
+	}{
+		{name: "500 with an HTML body", status: http.StatusInternalServerError, contentType: "text/html", body: "<html>panic</html>", wantErr: ErrSecuritySwitchServerError},
+		{name: "502 with a malformed JSON body", status: http.StatusBadGateway, contentType: "application/json", body: `{"error":`, wantErr: ErrSecuritySwitchServerError},
+		{name: "503 with a JSON body labelled HTML", status: http.StatusServiceUnavailable, contentType: "text/html", body: `{"error":"maintenance"}`, wantErr: ErrSecuritySwitchServerError},
+		{name: "500 with an empty body", status: http.StatusInternalServerError, body: "", wantErr: ErrSecuritySwitchServerError},
+		{name: "504 with Security-Switch's own JSON body is relayed", status: http.StatusGatewayTimeout, contentType: "application/json", body: `{"error":"the request could not be completed"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseURL, client, stop := newStub(t, func(w http.ResponseWriter, _ *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer stop()
+
+			result := Register(context.Background(), client, baseURL, validation.RegisterRequest{Email: testEmail, Password: testPassword, SSHPublicKey: testSSHPublicKey})
+
+			if tt.wantErr != nil {
+				if !errors.Is(result.Err, tt.wantErr) {
+					t.Fatalf("Err = %v, want wrapping %v", result.Err, tt.wantErr)
+				}
+				if result.StatusCode != 0 || result.Body != nil {
+					t.Fatalf("Result = %+v, want only Err set on a call failure", result)
+				}
+				return
+			}
+
+			if result.Err != nil {
+				t.Fatalf("Err = %v, want nil", result.Err)
+			}
+			if result.StatusCode != tt.status || string(result.Body) != tt.body {
+				t.Fatalf("Result = (%d, %q), want (%d, %q) relayed unchanged", result.StatusCode, result.Body, tt.status, tt.body)
+			}
+		})
+	}
+}
+
+// Requirement: EH-F-08
+// Requirement: EH-F-09
+func TestRegister_UnexpectedResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantErr     bool
+	}{
+		{name: "200 with an HTML body", status: http.StatusOK, contentType: "text/html", body: "<html>login portal</html>", wantErr: true},
+		{name: "200 with an empty body", status: http.StatusOK, body: "", wantErr: true},
+		{name: "400 with a plain-text body", status: http.StatusBadRequest, contentType: "text/plain", body: "bad request", wantErr: true},
+		{name: "201 with a JSON body labelled HTML", status: http.StatusCreated, contentType: "text/html", body: `{"posix_username":"user7k2m9x"}`, wantErr: true},
+		{name: "201 with a JSON body and charset is relayed", status: http.StatusCreated, contentType: "application/json; charset=utf-8", body: `{"posix_username":"user7k2m9x"}`},
+		{name: "409 with a JSON body and no Content-Type is relayed", status: http.StatusConflict, body: `{"error":"conflict"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseURL, client, stop := newStub(t, func(w http.ResponseWriter, _ *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				} else {
+					w.Header()["Content-Type"] = nil // suppress net/http's sniffed default
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			defer stop()
+
+			result := Register(context.Background(), client, baseURL, validation.RegisterRequest{Email: testEmail, Password: testPassword, SSHPublicKey: testSSHPublicKey})
+
+			if tt.wantErr {
+				if !errors.Is(result.Err, ErrSecuritySwitchUnexpectedResponse) {
+					t.Fatalf("Err = %v, want wrapping ErrSecuritySwitchUnexpectedResponse", result.Err)
+				}
+				if !strings.Contains(result.Err.Error(), strconv.Itoa(tt.status)) {
+					t.Fatalf("Err = %v, want it to name status %d", result.Err, tt.status)
+				}
+				return
+			}
+			if result.Err != nil {
+				t.Fatalf("Err = %v, want nil", result.Err)
+			}
+			if result.StatusCode != tt.status || string(result.Body) != tt.body {
+				t.Fatalf("Result = (%d, %q), want (%d, %q) relayed unchanged", result.StatusCode, result.Body, tt.status, tt.body)
+			}
+		})
+	}
+}
+
 // Requirement: EH-F-07
 // Requirement: EH-F-08
 func TestLogin_RelaysResponseUnchanged(t *testing.T) {
@@ -187,3 +291,22 @@ func TestLogin_RelaysUnauthorizedStatusUnchanged(t *testing.T) {
 		t.Fatalf("StatusCode = %d, want %d", result.StatusCode, http.StatusUnauthorized)
 	}
 }
+
+// Requirement: EH-F-07
+func TestPing(t *testing.T) {
+	baseURL, client, stop := newStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		http.NotFound(w, r)
+	})
+
+	if err := Ping(context.Background(), client, baseURL); err != nil {
+		t.Fatalf("Ping() error = %v, want nil for any complete response", err)
+	}
+
+	stop()
+	if err := Ping(context.Background(), client, baseURL); !errors.Is(err, ErrSecuritySwitchUnreachable) {
+		t.Fatalf("Ping() after stop error = %v, want ErrSecuritySwitchUnreachable", err)
+	}
+}
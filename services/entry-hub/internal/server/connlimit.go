@@ -0,0 +1,129 @@
+package server
+
+import (
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/Verryx-02/RAM-USB/pkg/logging"
+)
+
+// LimitListener wraps inner so that at most perIP connections from any
+// one remote IP address are open at once. A connection beyond that is
+// closed immediately after Accept, before the TLS handshake, so one
+// client opening connections in a loop cannot exhaust the file
+// descriptors and handshake CPU every other client shares. perIP <= 0
+// returns inner unchanged.
+//
+// Clients behind one NAT share an address and so share the limit; it is
+// meant as a ceiling against abuse, sized well above what any legitimate
+// client (which needs one or two keep-alive connections) would reach.
+func LimitListener(inner net.Listener, perIP int) net.Listener {
+	if perIP <= 0 {
+		return inner
+	}
+	return &limitListener{
+		Listener: inner,
+		perIP:    perIP,
+		open:     make(map[string]int),
+		limited:  make(map[string]bool),
+		logger:   slog.Default(),
+	}
+}
+
+// limitListener is LimitListener's net.Listener.
+type limitListener struct {
+	net.Listener
+	perIP  int
+	logger *slog.Logger
+
+	mu   sync.Mutex
+	open map[string]int
+
+	// limited holds each IP that has had a connection refused since it
+	// last dropped back under perIP, so a client reconnecting in a loop
+	// logs one warning per episode rather than one per refused
+	// connection.
+	limited map[string]bool
+}
+
+// Accept returns the next connection whose remote IP is under the limit,
+// silently closing any that are not.
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		ip := remoteIP(conn)
+		ok, firstRefusal := l.acquire(ip)
+		if ok {
+			return &limitConn{Conn: conn, release: func() { l.release(ip) }}, nil
+		}
+
+		if firstRefusal {
+			l.logger.Warn("entry-hub: refusing connections, per-IP limit reached",
+				"remote_ip", logging.Sanitize(ip), "limit", l.perIP)
+		}
+		_ = conn.Close()
+	}
+}
+
+// acquire counts a new connection from ip, unless ip already has perIP,
+// in which case firstRefusal reports whether this is the first refusal
+// since ip was last under the limit.
+func (l *limitListener) acquire(ip string) (ok, firstRefusal bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.open[ip] >= l.perIP {
+		firstRefusal = !l.limited[ip]
+		l.limited[ip] = true
+		return false, firstRefusal
+	}
+	l.open[ip]++
+	return true, false
+}
+
+// release uncounts a closed connection from ip, logging once if ip had
+// been refused connections and is now back under the limit.
+func (l *limitListener) release(ip string) {
+	l.mu.Lock()
+	l.open[ip]--
+	if l.open[ip] <= 0 {
+		delete(l.open, ip)
+	}
+	wasLimited := l.limited[ip]
+	delete(l.limited, ip)
+	l.mu.Unlock()
+
+	if wasLimited {
+		l.logger.Info("entry-hub: accepting connections again, back under per-IP limit",
+			"remote_ip", logging.Sanitize(ip), "limit", l.perIP)
+	}
+}
+
+// remoteIP returns conn's remote address without its port, or the whole
+// address if it has none.
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// limitConn releases its slot exactly once, however many times Close is
+// called (net/http may close a connection more than once).
+type limitConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+// Close closes the connection and releases its slot.
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// acceptAll accepts connections from l until it is closed, sending each
+// on the returned channel.
+func acceptAll(l net.Listener) <-chan net.Conn {
+	accepted := make(chan net.Conn, 16)
+	go func() {
+		defer close(accepted)
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+	return accepted
+}
+
+// waitAccepted returns the next accepted connection, or nil if none
+// arrives within a short wait.
+func waitAccepted(accepted <-chan net.Conn) net.Conn {
+	select {
+	case conn := <-accepted:
+		return conn
+	case <-time.After(200 * time.Millisecond):
+		return nil
+	}
+}
+
+// Requirement: EH-F-01
+func TestLimitListener_RefusesConnectionsBeyondPerIPLimit(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	l := LimitListener(inner, 2)
+	t.Cleanup(func() { _ = l.Close() })
+	accepted := acceptAll(l)
+
+	var clients []net.Conn
+	for i := 0; i < 3; i++ {
+		client, err := net.Dial("tcp", inner.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial() #%d error = %v", i, err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		clients = append(clients, client)
+	}
+
+	var served []net.Conn
+	for {
+		conn := waitAccepted(accepted)
+		if conn == nil {
+			break
+		}
+		served = append(served, conn)
+	}
+	if len(served) != 2 {
+		t.Fatalf("accepted %d connections, want 2", len(served))
+	}
+
+	// The third client's connection was closed by the listener.
+	_ = clients[2].SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := clients[2].Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+		t.Fatalf("third client Read() error = %v, want io.EOF", err)
+	}
+
+	// Closing a served connection frees its slot for a new one.
+	_ = served[0].Close()
+	_ = served[0].Close() // a second Close must not free a second slot
+	for i := 0; i < 2; i++ {
+		client, err := net.Dial("tcp", inner.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial() after close error = %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+	}
+	if conn := waitAccepted(accepted); conn == nil {
+		t.Fatal("no connection accepted after a slot was freed")
+	}
+	if conn := waitAccepted(accepted); conn != nil {
+		t.Fatal("accepted a connection beyond the limit after a double Close")
+	}
+}
+
+// Requirement: EH-F-01
+func TestLimitListener_NonPositiveLimitIsUnwrapped(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = inner.Close() })
+
+	if got := LimitListener(inner, 0); got != inner {
+		t.Fatal("LimitListener(inner, 0) wrapped inner, want it returned unchanged")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe to write from the accepting goroutine
+// while the test reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Requirement: EH-F-01
+func TestLimitListener_LogsOncePerEpisode(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	l := LimitListener(inner, 1)
+	var logBuf syncBuffer
+	l.(*limitListener).logger = slog.New(slog.NewTextHandler(&logBuf, nil))
+	t.Cleanup(func() { _ = l.Close() })
+	accepted := acceptAll(l)
+
+	dial := func() net.Conn {
+		t.Helper()
+		client, err := net.Dial("tcp", inner.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+
+	dial()
+	served := waitAccepted(accepted)
+	if served == nil {
+		t.Fatal("first connection not accepted")
+	}
+	for i := 0; i < 3; i++ {
+		refused := dial()
+		_ = refused.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := refused.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+			t.Fatalf("refused client #%d Read() error = %v, want io.EOF", i, err)
+		}
+	}
+	if got := strings.Count(logBuf.String(), "per-IP limit reached"); got != 1 {
+		t.Fatalf("logged %d refusal warnings for three refused connections, want 1:\n%s", got, logBuf.String())
+	}
+
+	_ = served.Close()
+	if got := strings.Count(logBuf.String(), "back under per-IP limit"); got != 1 {
+		t.Fatalf("logged %d recovery lines after the slot was freed, want 1:\n%s", got, logBuf.String())
+	}
+}
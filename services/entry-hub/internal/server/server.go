@@ -1,5 +1,6 @@
 // Package server holds Entry-Hub's connection-acceptance logic: the TLS
-// configuration for its public-facing listener (EH-F-01, EH-F-02, EH-F-03).
+// configuration for its public-facing listener (EH-F-01, EH-F-02, EH-F-03),
+// and the per-IP connection cap in front of it (LimitListener).
 //
 // Unlike every other service in this codebase (Database-Vault, Security-
 // Switch, Storage-Service), Entry-Hub's inbound listener is NOT mTLS.
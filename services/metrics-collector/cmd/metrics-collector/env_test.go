@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Requirement: RD-04
+func TestGetEnvDuration(t *testing.T) {
+	const name = "RAM_USB_METRICS_COLLECTOR_TEST_DURATION"
+	const fallback = time.Minute
+
+	tests := []struct {
+		value           string
+		want            time.Duration
+		wantErr         bool
+		wantNonNegative time.Duration
+		nonNegativeErr  bool
+	}{
+		{value: "", want: fallback, wantNonNegative: fallback},
+		{value: "90s", want: 90 * time.Second, wantNonNegative: 90 * time.Second},
+		{value: "0s", wantErr: true, wantNonNegative: 0},
+		{value: "-5s", wantErr: true, nonNegativeErr: true},
+		{value: "soon", wantErr: true, nonNegativeErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(name, tt.value)
+
+			got, err := getEnvDuration(name, fallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getEnvDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Fatalf("getEnvDuration() = %s, want %s", got, tt.want)
+			}
+
+			got, err = getEnvNonNegativeDuration(name, fallback)
+			if (err != nil) != tt.nonNegativeErr {
+				t.Fatalf("getEnvNonNegativeDuration() error = %v, wantErr %v", err, tt.nonNegativeErr)
+			}
+			if err == nil && got != tt.wantNonNegative {
+				t.Fatalf("getEnvNonNegativeDuration() = %s, want %s", got, tt.wantNonNegative)
+			}
+		})
+	}
+}
@@ -52,8 +52,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -62,7 +64,10 @@ import (
 
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/pkg/metrics"
+	"github.com/Verryx-02/RAM-USB/pkg/pgpool"
 	"github.com/Verryx-02/RAM-USB/pkg/pki"
+	"github.com/Verryx-02/RAM-USB/pkg/secrets"
+	"github.com/Verryx-02/RAM-USB/services/metrics-collector/internal/alert"
 	"github.com/Verryx-02/RAM-USB/services/metrics-collector/internal/collector"
 	"github.com/Verryx-02/RAM-USB/services/metrics-collector/internal/schema"
 	"github.com/Verryx-02/RAM-USB/services/metrics-collector/internal/store"
@@ -75,9 +80,25 @@ const (
 	envMQTTBrokerURL = "RAM_USB_MQTT_BROKER_URL"
 
 	// envDatabaseURL is the TimescaleDB/Postgres connection string
-	// pgxpool.New parses (MT-F-03).
+	// pgxpool.New parses (MT-F-03). It carries the database password, so
+	// it may instead be supplied as a file (see requireSecretEnv).
+	// Its pool_min_conns parameter (default 0, meaning one) sets how many
+	// connections pgpool.Warm opens at startup.
+	// Its sslmode must be require or stricter unless
+	// envDatabaseAllowPlaintext is set (pgpool.RequireTLS).
 	envDatabaseURL = "RAM_USB_METRICS_COLLECTOR_DATABASE_URL"
 
+	// envDatabaseAllowPlaintext, set to true, skips pgpool.RequireTLS's
+	// check on envDatabaseURL, for a development database reached over a
+	// private container network without TLS. Optional: defaults to false;
+	// a value that is not a bool fails startup (RD-04).
+	envDatabaseAllowPlaintext = "RAM_USB_METRICS_COLLECTOR_DATABASE_ALLOW_PLAINTEXT"
+
+	// envDatabasePoolStatsInterval is how often pgpool.MonitorStats logs
+	// the database pool's connection counts and checks them for a leak,
+	// as a Go duration string. Optional: unset or "0s" disables it.
+	envDatabasePoolStatsInterval = "RAM_USB_METRICS_COLLECTOR_DATABASE_POOL_STATS_INTERVAL"
+
 	// envMigrationsDir locates the directory of SQL migration files
 	// (internal/schema.Apply) applied once at startup, before this
 	// process starts accepting MQTT messages. Optional: defaults to
@@ -85,8 +106,113 @@ const (
 	// repository's root — same convention as Database-Vault's own
 	// envMigrationsDir.
 	envMigrationsDir = "RAM_USB_METRICS_COLLECTOR_MIGRATIONS_DIR"
+
+	// envDatabaseStartupTimeout bounds how long startup keeps retrying
+	// (internal/schema.NewWithRetry) a database that is not yet accepting
+	// connections, e.g. because its container is still starting. A Go
+	// duration string; optional, defaulting to
+	// defaultDatabaseStartupTimeout, and must be positive. Once spent,
+	// startup fails (RD-04).
+	envDatabaseStartupTimeout = "RAM_USB_METRICS_COLLECTOR_DATABASE_STARTUP_TIMEOUT"
+
+	// envRejectionMode selects what happens to a message
+	// internal/collector.Handler discards (MT-F-02): "drop" (the default)
+	// only logs it; "quarantine" additionally records its topic and
+	// discard reason in the "rejected_metrics" table, for diagnosing a
+	// misbehaving publisher later. Any other value fails startup (RD-04)
+	// rather than silently falling back to either mode.
+	envRejectionMode = "RAM_USB_METRICS_COLLECTOR_REJECTION_MODE"
+
+	// envMaxFutureSkew is how far ahead of this host's clock a payload's
+	// timestamp may be before internal/collector.Handler discards it (a Go
+	// duration string). Optional: unset or "0" stores every timestamp
+	// as-is. A drifting publisher's discards are logged with a running
+	// clock_skew_rejections_total, and quarantined as "clock_skew" in
+	// quarantine mode. A negative or unparseable value fails startup
+	// (RD-04).
+	envMaxFutureSkew = "RAM_USB_METRICS_COLLECTOR_MAX_FUTURE_SKEW"
+
+	// envQueueSize, envQueueWorkers, envStoreRetries, and
+	// envStoreRetryBackoff tune internal/collector.Queue, which moves
+	// storage off the MQTT callback goroutine: how many received messages
+	// may wait for storage, how many are stored concurrently, how many
+	// extra attempts a failed insert gets, and how long to wait between
+	// them (a Go duration string). All optional, defaulting to the
+	// matching default* constant below; a value present but unparseable or
+	// out of range fails startup (RD-04).
+	envQueueSize         = "RAM_USB_METRICS_COLLECTOR_QUEUE_SIZE"
+	envQueueWorkers      = "RAM_USB_METRICS_COLLECTOR_QUEUE_WORKERS"
+	envStoreRetries      = "RAM_USB_METRICS_COLLECTOR_STORE_RETRIES"
+	envStoreRetryBackoff = "RAM_USB_METRICS_COLLECTOR_STORE_RETRY_BACKOFF"
+
+	// envAlertWebhookURL is where internal/alert.Webhook POSTs each alert
+	// as JSON. Optional: unset disables alerting entirely, the same way an
+	// unset RAM_USB_MQTT_BROKER_URL disables publishing elsewhere.
+	envAlertWebhookURL = "RAM_USB_METRICS_COLLECTOR_ALERT_WEBHOOK_URL"
+
+	// envAlertInterval, envAlertSilenceThreshold, and envAlertRejectionRate
+	// tune internal/alert.Engine: how often its rules are evaluated (a Go
+	// duration string), how long a service may go without a stored row
+	// before it counts as silent (likewise), and the rejected share of
+	// messages, in (0, 1], that fires the rejection-rate rule. Optional,
+	// defaulting to the matching default* constant below; a value present
+	// but unparseable or out of range fails startup (RD-04).
+	envAlertInterval         = "RAM_USB_METRICS_COLLECTOR_ALERT_INTERVAL"
+	envAlertSilenceThreshold = "RAM_USB_METRICS_COLLECTOR_ALERT_SILENCE_THRESHOLD"
+	envAlertRejectionRate    = "RAM_USB_METRICS_COLLECTOR_ALERT_REJECTION_RATE"
+
+	// envAlertErrorRate is the share of one service's requests, in
+	// (0, 1], that must have ended in an error over the alert window for
+	// internal/alert.RuleServiceDegraded to fire for that service.
+	// Optional and validated like envAlertRejectionRate.
+	envAlertErrorRate = "RAM_USB_METRICS_COLLECTOR_ALERT_ERROR_RATE"
+)
+
+// Defaults for envQueueSize/envQueueWorkers/envStoreRetries/
+// envStoreRetryBackoff. Each publishing service sends one message a
+// minute, so a 1000-message queue rides out a database outage of several
+// minutes across every service before dropping anything.
+const (
+	defaultQueueSize         = 1000
+	defaultQueueWorkers      = 4
+	defaultStoreRetries      = 2
+	defaultStoreRetryBackoff = time.Second
+)
+
+// Defaults for envAlertInterval/envAlertSilenceThreshold/
+// envAlertRejectionRate/envAlertErrorRate. Every service publishes once a
+// minute, so five minutes of silence is several consecutive missed
+// publishes.
+const (
+	defaultAlertInterval         = time.Minute
+	defaultAlertSilenceThreshold = 5 * time.Minute
+	defaultAlertRejectionRate    = 0.5
+	defaultAlertErrorRate        = 0.1
 )
 
+// alertWindow is the lookback internal/alert.Engine computes the
+// rejection and error rates over.
+const alertWindow = 15 * time.Minute
+
+// alertWebhookTimeout bounds one webhook delivery.
+const alertWebhookTimeout = 10 * time.Second
+
+// Values accepted for envRejectionMode.
+const (
+	rejectionModeDrop       = "drop"
+	rejectionModeQuarantine = "quarantine"
+)
+
+// defaultDatabaseStartupTimeout is envDatabaseStartupTimeout's fallback:
+// long enough for a freshly started TimescaleDB container to finish
+// initializing.
+const defaultDatabaseStartupTimeout = 60 * time.Second
+
+// poolLeakSamples is how many consecutive pgpool.MonitorStats samples
+// with no idle connection make a suspected leak: at a one-minute
+// interval, five minutes of a fully acquired pool.
+const poolLeakSamples = 5
+
 // defaultMigrationsDir is envMigrationsDir's fallback: the migrations
 // directory's checked-in location relative to this repository's root.
 const defaultMigrationsDir = "services/metrics-collector/migrations"
@@ -97,6 +223,15 @@ const defaultMigrationsDir = "services/metrics-collector/migrations"
 // own metricsClientID constant.
 const metricsClientID = "metrics-collector"
 
+// serviceName is the "service" value of the rows this process stores
+// about its own database pool (see ownMetricsInterval). No publisher uses
+// it, so they can never be mistaken for, or overwrite, a published row.
+const serviceName = "Metrics-Collector"
+
+// ownMetricsInterval is how often this process stores its own pool
+// gauges: once a minute, like every publishing service (DV-F-16).
+const ownMetricsInterval = time.Minute
+
 // connectTimeout bounds how long this process waits for the MQTT broker
 // connection to complete at startup.
 const connectTimeout = 10 * time.Second
@@ -111,6 +246,8 @@ const subscribeTopic = "metrics/#"
 const subscribeQoS byte = 1
 
 func main() {
+	slog.SetDefault(logging.NewLogger(os.Stderr))
+
 	if err := run(); err != nil {
 		slog.Error("metrics-collector: fatal startup error", "error", logging.Sanitize(err.Error()))
 		os.Exit(1)
@@ -121,13 +258,37 @@ func run() error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	databaseURL, err := requireEnv(envDatabaseURL)
+	databaseURL, err := requireSecretEnv(envDatabaseURL)
+	if err != nil {
+		return err
+	}
+	allowPlaintext, err := getEnvBool(envDatabaseAllowPlaintext)
+	if err != nil {
+		return err
+	}
+	if allowPlaintext {
+		slog.Warn("metrics-collector: database connection may be unencrypted, " + envDatabaseAllowPlaintext + " is set")
+	} else if err := pgpool.RequireTLS(databaseURL); err != nil {
+		return fmt.Errorf("%s: %w", envDatabaseURL, err)
+	}
+
+	rejectionMode := getEnvOrDefault(envRejectionMode, rejectionModeDrop)
+	if rejectionMode != rejectionModeDrop && rejectionMode != rejectionModeQuarantine {
+		return fmt.Errorf("%s must be %q or %q, got %q", envRejectionMode, rejectionModeDrop, rejectionModeQuarantine, rejectionMode)
+	}
+
+	maxFutureSkew, err := getEnvNonNegativeDuration(envMaxFutureSkew, 0)
+	if err != nil {
+		return err
+	}
+
+	startupTimeout, err := getEnvDuration(envDatabaseStartupTimeout, defaultDatabaseStartupTimeout)
 	if err != nil {
 		return err
 	}
 
 	migrationsDir := getEnvOrDefault(envMigrationsDir, defaultMigrationsDir)
-	migration, err := schema.New(databaseURL, migrationsDir)
+	migration, err := schema.NewWithRetry(ctx, databaseURL, migrationsDir, startupTimeout)
 	if err != nil {
 		return fmt.Errorf("build schema migration: %w", err)
 	}
@@ -146,15 +307,80 @@ func run() error {
 	}
 	defer pool.Close()
 
+	// Open the pool's pool_min_conns connections now rather than on the
+	// first requests (pkg/pgpool's doc comment).
+	if err := pgpool.Warm(ctx, pool); err != nil {
+		return fmt.Errorf("warm database pool: %w", err)
+	}
+
+	poolStatsInterval, err := getEnvNonNegativeDuration(envDatabasePoolStatsInterval, 0)
+	if err != nil {
+		return err
+	}
+	// leakDetector stays nil, never suspecting a leak, unless the stats
+	// monitor runs to feed it.
+	var leakDetector *pgpool.LeakDetector
+	if poolStatsInterval > 0 {
+		leakDetector = &pgpool.LeakDetector{Samples: poolLeakSamples}
+		go pgpool.MonitorStats(ctx, pool, poolStatsInterval, leakDetector)
+	}
+
+	if err := store.CheckHypertable(ctx, pool); err != nil {
+		return fmt.Errorf("verify metrics hypertable: %w", err)
+	}
+
 	mqttClient, err := buildMQTTClient(ctx)
 	if err != nil {
 		return fmt.Errorf("build mqtt client: %w", err)
 	}
 	defer mqttClient.Disconnect(250)
 
-	handler := &collector.Handler{Store: store.Store{DB: store.PoolQuerier{Pool: pool}}}
+	metricsStore := store.Store{DB: store.PoolQuerier{Pool: pool}}
+	handler := &collector.Handler{Store: metricsStore, MaxFutureSkew: maxFutureSkew}
+	if rejectionMode == rejectionModeQuarantine {
+		handler.Quarantine = metricsStore
+	}
+
+	queue, err := buildQueue(handler)
+	if err != nil {
+		return fmt.Errorf("build message queue: %w", err)
+	}
+
+	// queueCtx is cancelled explicitly on every return path, not only via
+	// ctx's signal, so an early startup error below cannot leave this
+	// deferred wait blocked on workers that never stop. Waiting here
+	// (deferred after, so run before, the pool/MQTT cleanup above) keeps
+	// the pool open until every in-flight insert has finished.
+	queueCtx, cancelQueue := context.WithCancel(ctx)
+	queueDone := make(chan struct{})
+	go func() {
+		queue.Run(queueCtx)
+		close(queueDone)
+	}()
+	defer func() {
+		cancelQueue()
+		<-queueDone
+	}()
 
-	token := mqttClient.Subscribe(subscribeTopic, subscribeQoS, handler.OnMessage)
+	// This process publishes nothing over MQTT, so it stores its own
+	// database pool gauges (MT-F-04) directly, next to the rows of the
+	// services it collects from.
+	go metrics.Run(ctx, ownMetricsInterval, func(storeCtx context.Context) error {
+		var counters metrics.Counters
+		pgpool.StatsOf(pool).Record(&counters)
+		leakDetector.Record(&counters)
+		return metricsStore.Insert(storeCtx, metrics.NewPayload(serviceName, counters, time.Now()))
+	})
+
+	alertEngine, alertInterval, err := buildAlertEngine(pool)
+	if err != nil {
+		return fmt.Errorf("build alert engine: %w", err)
+	}
+	if alertEngine != nil {
+		go alertEngine.Run(ctx, alertInterval)
+	}
+
+	token := mqttClient.Subscribe(subscribeTopic, subscribeQoS, queue.OnMessage)
 	if !token.WaitTimeout(connectTimeout) {
 		return fmt.Errorf("subscribe to %s timed out after %s", subscribeTopic, connectTimeout)
 	}
@@ -162,7 +388,7 @@ func run() error {
 		return fmt.Errorf("subscribe to %s: %w", subscribeTopic, err)
 	}
 
-	slog.Info("metrics-collector: subscribed", "topic", subscribeTopic)
+	slog.Info("metrics-collector: subscribed", "topic", subscribeTopic, "rejection_mode", rejectionMode)
 
 	<-ctx.Done()
 	return nil
@@ -178,6 +404,37 @@ func requireEnv(name string) (string, error) {
 	return value, nil
 }
 
+// requireSecretEnv is requireEnv for a value carrying a credential: name
+// may instead be supplied as a file whose path is in name+"_FILE"
+// (pkg/secrets), which takes precedence, so the credential need not sit
+// in the process environment at all.
+func requireSecretEnv(name string) (string, error) {
+	value, ok, err := secrets.Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("required environment variable %s (or %s%s) is not set", name, name, secrets.FileSuffix)
+	}
+	return value, nil
+}
+
+// getEnvBool reads name from the environment as a bool, defaulting to
+// false if unset or empty. A value present but not parseable as a bool
+// (strconv.ParseBool's accepted forms) is a startup failure (RD-04,
+// fail-secure) - not silently treated as false.
+func getEnvBool(name string) (bool, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("environment variable %s is not a valid bool: %w", name, err)
+	}
+	return parsed, nil
+}
+
 // getEnvOrDefault reads name from the environment, returning fallback if
 // it is unset or empty.
 func getEnvOrDefault(name, fallback string) string {
@@ -188,6 +445,133 @@ func getEnvOrDefault(name, fallback string) string {
 	return value
 }
 
+// getEnvInt reads name from the environment as a base-10 integer,
+// returning fallback if it is unset or empty. A value present but
+// unparseable is a startup failure (RD-04), not silently replaced by
+// fallback.
+func getEnvInt(name string, fallback int) (int, error) {
+	value := getEnvOrDefault(name, "")
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s is not a valid integer: %w", name, err)
+	}
+	return parsed, nil
+}
+
+// getEnvDuration reads name from the environment as a Go duration string
+// (time.ParseDuration), returning fallback if it is unset or empty. A
+// value present but unparseable, or not positive, is a startup failure
+// (RD-04).
+func getEnvDuration(name string, fallback time.Duration) (time.Duration, error) {
+	parsed, err := getEnvNonNegativeDuration(name, fallback)
+	if err != nil {
+		return 0, err
+	}
+	if parsed == 0 {
+		return 0, fmt.Errorf("environment variable %s must be positive, got %s", name, parsed)
+	}
+	return parsed, nil
+}
+
+// getEnvNonNegativeDuration is getEnvDuration for a setting where zero
+// has a meaning of its own, usually "disabled": only an unparseable or
+// negative value is a startup failure (RD-04).
+func getEnvNonNegativeDuration(name string, fallback time.Duration) (time.Duration, error) {
+	value := getEnvOrDefault(name, "")
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s is not a valid duration: %w", name, err)
+	}
+	if parsed < 0 {
+		return 0, fmt.Errorf("environment variable %s must not be negative, got %s", name, parsed)
+	}
+	return parsed, nil
+}
+
+// buildQueue reads envQueueSize/envQueueWorkers/envStoreRetries/
+// envStoreRetryBackoff into a collector.Queue feeding handler.
+func buildQueue(handler *collector.Handler) (*collector.Queue, error) {
+	size, err := getEnvInt(envQueueSize, defaultQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	workers, err := getEnvInt(envQueueWorkers, defaultQueueWorkers)
+	if err != nil {
+		return nil, err
+	}
+	retries, err := getEnvInt(envStoreRetries, defaultStoreRetries)
+	if err != nil {
+		return nil, err
+	}
+	backoff, err := getEnvNonNegativeDuration(envStoreRetryBackoff, defaultStoreRetryBackoff)
+	if err != nil {
+		return nil, err
+	}
+
+	return collector.NewQueue(handler, size, workers, retries, backoff)
+}
+
+// buildAlertEngine reads envAlertWebhookURL and its tuning values into an
+// alert.Engine over pool. A nil engine (with no error) means alerting is
+// not configured.
+func buildAlertEngine(pool *pgxpool.Pool) (*alert.Engine, time.Duration, error) {
+	webhookURL := getEnvOrDefault(envAlertWebhookURL, "")
+	if webhookURL == "" {
+		slog.Info("metrics-collector: alerting disabled, " + envAlertWebhookURL + " is not set")
+		return nil, 0, nil
+	}
+
+	interval, err := getEnvDuration(envAlertInterval, defaultAlertInterval)
+	if err != nil {
+		return nil, 0, err
+	}
+	silence, err := getEnvDuration(envAlertSilenceThreshold, defaultAlertSilenceThreshold)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rate, err := getEnvRate(envAlertRejectionRate, defaultAlertRejectionRate)
+	if err != nil {
+		return nil, 0, err
+	}
+	errorRate, err := getEnvRate(envAlertErrorRate, defaultAlertErrorRate)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &alert.Engine{
+		Source:                 store.Reader{DB: pool},
+		Alerter:                alert.Webhook{Client: &http.Client{Timeout: alertWebhookTimeout}, URL: webhookURL},
+		SilenceThreshold:       silence,
+		Window:                 alertWindow,
+		RejectionRateThreshold: rate,
+		ErrorRateThreshold:     errorRate,
+	}, interval, nil
+}
+
+// getEnvRate reads name as a share in (0, 1], returning fallback if it
+// is unset.
+func getEnvRate(name string, fallback float64) (float64, error) {
+	rate := fallback
+	if value := getEnvOrDefault(name, ""); value != "" {
+		var err error
+		rate, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("environment variable %s is not a valid number: %w", name, err)
+		}
+	}
+	if rate <= 0 || rate > 1 {
+		return 0, fmt.Errorf("%s must be greater than 0 and at most 1, got %v", name, rate)
+	}
+	return rate, nil
+}
+
 // buildMQTTClient assembles and connects the mTLS MQTT client this
 // process subscribes with, bootstrapping its own mTLS identity directly
 // via pki.NewClient (CA-F-04) - this process has no inbound listener or
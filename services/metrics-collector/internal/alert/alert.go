@@ -0,0 +1,89 @@
+// Package alert lets Metrics-Collector notify an operator proactively
+// when the metrics it stores cross a threshold, rather than waiting for
+// someone to query TimescaleDB. An Engine evaluates a fixed set of rules
+// against recent metrics on a timer and hands each newly firing Alert to
+// an Alerter; Webhook, the one Alerter implementation, POSTs it as JSON
+// to an operator-configured URL.
+//
+// Alerts stay zero-knowledge in the same sense as the metrics they are
+// derived from (EH-F-10 and its equivalents): they carry only a rule
+// name, a service name, and aggregate numbers, never anything about an
+// individual request or user.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Rule names carried in Alert.Rule.
+const (
+	// RuleServiceSilent fires when a service that has published metrics
+	// before has not done so for longer than Engine.SilenceThreshold.
+	RuleServiceSilent = "service_silent"
+
+	// RuleRejectionRate fires when the share of received messages
+	// discarded (MT-F-02) over Engine.Window reaches
+	// Engine.RejectionRateThreshold.
+	RuleRejectionRate = "rejection_rate"
+
+	// RuleServiceDegraded fires when the share of one service's requests
+	// that ended in an error (the error_count/request_count each payload
+	// reports, MT-F-04) over Engine.Window reaches
+	// Engine.ErrorRateThreshold.
+	RuleServiceDegraded = "service_degraded"
+)
+
+// Alert is one firing rule, and the exact JSON body Webhook posts.
+type Alert struct {
+	Rule string `json:"rule"`
+	// Service is the silent service for RuleServiceSilent and the
+	// degraded one for RuleServiceDegraded, empty for collector-wide
+	// rules.
+	Service   string    `json:"service,omitempty"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+// Alerter delivers an Alert to wherever an operator will see it.
+type Alerter interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+// Webhook is an Alerter that POSTs each Alert as JSON to URL.
+type Webhook struct {
+	Client *http.Client
+	URL    string
+}
+
+// Send POSTs alert to w.URL. Any non-2xx response is an error.
+func (w Webhook) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("alert: marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("alert: build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alert: post webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("alert: webhook responded %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Requirement: MT-F-03
+func TestWebhook_PostsAlertAsJSON(t *testing.T) {
+	firedAt := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	want := Alert{Rule: RuleServiceSilent, Service: "Entry-Hub", Value: 600, Threshold: 300, FiredAt: firedAt}
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := (Webhook{Client: srv.Client(), URL: srv.URL}).Send(context.Background(), want); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	wantBody := map[string]any{
+		"rule":      RuleServiceSilent,
+		"service":   "Entry-Hub",
+		"value":     float64(600),
+		"threshold": float64(300),
+		"fired_at":  "2026-10-15T12:00:00Z",
+	}
+	if len(got) != len(wantBody) {
+		t.Fatalf("webhook body = %v, want exactly the keys of %v", got, wantBody)
+	}
+	for k, v := range wantBody {
+		if got[k] != v {
+			t.Fatalf("webhook body[%q] = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+// Requirement: MT-F-03
+func TestWebhook_NonSuccessStatusIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := (Webhook{Client: srv.Client(), URL: srv.URL}).Send(context.Background(), Alert{Rule: RuleRejectionRate}); err == nil {
+		t.Fatal("Send() error = nil, want an error for a 500 response")
+	}
+}
@@ -0,0 +1,174 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/logging"
+)
+
+// Source is the read side of the metrics store an Engine evaluates its
+// rules against. internal/store.Reader implements it.
+type Source interface {
+	// LastSeen returns, per service, the time of its newest stored
+	// metrics row.
+	LastSeen(ctx context.Context) (map[string]time.Time, error)
+	// Counts returns how many messages were stored and how many were
+	// rejected since the given time.
+	Counts(ctx context.Context, since time.Time) (stored, rejected int64, err error)
+	// ErrorCounts returns, per service, how many requests and errors it
+	// handled since the given time. Publishers report running totals, so
+	// this is how much those totals grew, not their sum.
+	ErrorCounts(ctx context.Context, since time.Time) (requests, errs map[string]int64, err error)
+}
+
+// Engine evaluates RuleServiceSilent, RuleRejectionRate, and
+// RuleServiceDegraded against Source and sends each alert through
+// Alerter. An alert is sent once when its condition starts holding, not
+// again on every evaluation while it keeps holding; once the condition
+// clears, a later recurrence alerts again.
+type Engine struct {
+	Source  Source
+	Alerter Alerter
+
+	// SilenceThreshold is how long a service may go without a stored
+	// metrics row before RuleServiceSilent fires. Every service publishes
+	// once a minute, so a few minutes tolerates a missed publish or two.
+	SilenceThreshold time.Duration
+
+	// Window is the lookback RuleRejectionRate is computed over, and
+	// RejectionRateThreshold the rejected/(stored+rejected) share, in
+	// (0, 1], at which it fires. Rejections are only recorded in
+	// quarantine mode (see cmd/metrics-collector's envRejectionMode); in
+	// drop mode the rate is always 0 and this rule never fires.
+	Window                 time.Duration
+	RejectionRateThreshold float64
+
+	// ErrorRateThreshold is the errors/requests share, in (0, 1], over
+	// Window at which RuleServiceDegraded fires for a service. A service
+	// that reported no requests in Window has no error rate and never
+	// fires it.
+	ErrorRateThreshold float64
+
+	// active holds the keys (see key) of alerts currently firing. Only
+	// Evaluate, called from one goroutine, touches it.
+	active map[string]bool
+}
+
+// Run calls Evaluate every interval until ctx is done. Evaluation and
+// delivery failures are logged and do not stop the loop.
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := e.Evaluate(ctx, now); err != nil {
+				slog.Error("metrics-collector: alert evaluation failed", "error", logging.Sanitize(err.Error()))
+			}
+		}
+	}
+}
+
+// Evaluate checks every rule as of now and sends each alert that has
+// started firing since the previous call. A failed Send is logged, and
+// the alert is retried on the next call.
+func (e *Engine) Evaluate(ctx context.Context, now time.Time) error {
+	firing, err := e.firing(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	next := make(map[string]bool, len(firing))
+	for _, a := range firing {
+		k := key(a)
+		if e.active[k] {
+			next[k] = true
+			continue
+		}
+		if err := e.Alerter.Send(ctx, a); err != nil {
+			slog.Error("metrics-collector: alert delivery failed",
+				"rule", a.Rule, "service", logging.Sanitize(a.Service), "error", logging.Sanitize(err.Error()))
+			continue
+		}
+		next[k] = true
+	}
+	e.active = next
+	return nil
+}
+
+// firing returns every alert whose condition holds as of now, in a stable
+// order.
+func (e *Engine) firing(ctx context.Context, now time.Time) ([]Alert, error) {
+	var alerts []Alert
+
+	lastSeen, err := e.Source.LastSeen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("alert: read last-seen times: %w", err)
+	}
+	for service, seen := range lastSeen {
+		if silent := now.Sub(seen); silent > e.SilenceThreshold {
+			alerts = append(alerts, Alert{
+				Rule:      RuleServiceSilent,
+				Service:   service,
+				Value:     silent.Seconds(),
+				Threshold: e.SilenceThreshold.Seconds(),
+				FiredAt:   now,
+			})
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Service < alerts[j].Service })
+
+	stored, rejected, err := e.Source.Counts(ctx, now.Add(-e.Window))
+	if err != nil {
+		return nil, fmt.Errorf("alert: read message counts: %w", err)
+	}
+	if total := stored + rejected; total > 0 {
+		rate := float64(rejected) / float64(total)
+		if rate >= e.RejectionRateThreshold {
+			alerts = append(alerts, Alert{
+				Rule:      RuleRejectionRate,
+				Value:     rate,
+				Threshold: e.RejectionRateThreshold,
+				FiredAt:   now,
+			})
+		}
+	}
+
+	requests, errs, err := e.Source.ErrorCounts(ctx, now.Add(-e.Window))
+	if err != nil {
+		return nil, fmt.Errorf("alert: read per-service error counts: %w", err)
+	}
+	var degraded []Alert
+	for service, total := range requests {
+		if total <= 0 {
+			continue
+		}
+		rate := float64(errs[service]) / float64(total)
+		if rate >= e.ErrorRateThreshold {
+			degraded = append(degraded, Alert{
+				Rule:      RuleServiceDegraded,
+				Service:   service,
+				Value:     rate,
+				Threshold: e.ErrorRateThreshold,
+				FiredAt:   now,
+			})
+		}
+	}
+	sort.Slice(degraded, func(i, j int) bool { return degraded[i].Service < degraded[j].Service })
+	alerts = append(alerts, degraded...)
+
+	return alerts, nil
+}
+
+// key identifies an alert across evaluations, independent of its
+// changing Value and FiredAt.
+func key(a Alert) string {
+	return a.Rule + "/" + a.Service
+}
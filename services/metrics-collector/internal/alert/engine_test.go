@@ -0,0 +1,207 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeSource is a hand-written fake of Source (CONTRIBUTING.md §7.5).
+type fakeSource struct {
+	lastSeen map[string]time.Time
+	stored   int64
+	rejected int64
+	requests map[string]int64
+	errors   map[string]int64
+	err      error
+}
+
+func (f *fakeSource) LastSeen(context.Context) (map[string]time.Time, error) {
+	return f.lastSeen, f.err
+}
+
+func (f *fakeSource) Counts(context.Context, time.Time) (int64, int64, error) {
+	return f.stored, f.rejected, f.err
+}
+
+func (f *fakeSource) ErrorCounts(context.Context, time.Time) (map[string]int64, map[string]int64, error) {
+	return f.requests, f.errors, f.err
+}
+
+// fakeAlerter is a hand-written fake of Alerter (CONTRIBUTING.md §7.5),
+// recording every alert it is asked to send.
+type fakeAlerter struct {
+	sent []Alert
+	err  error
+}
+
+func (f *fakeAlerter) Send(_ context.Context, a Alert) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, a)
+	return nil
+}
+
+func newTestEngine(source *fakeSource, alerter *fakeAlerter) *Engine {
+	return &Engine{
+		Source:                 source,
+		Alerter:                alerter,
+		SilenceThreshold:       5 * time.Minute,
+		Window:                 15 * time.Minute,
+		RejectionRateThreshold: 0.5,
+		ErrorRateThreshold:     0.1,
+	}
+}
+
+// Requirement: MT-F-03
+func TestEngine_Evaluate_Rules(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		source fakeSource
+		want   []Alert
+	}{
+		{
+			name:   "every service recent, no rejections",
+			source: fakeSource{lastSeen: map[string]time.Time{"Entry-Hub": now.Add(-time.Minute)}, stored: 10},
+		},
+		{
+			name: "one service silent",
+			source: fakeSource{lastSeen: map[string]time.Time{
+				"Entry-Hub":       now.Add(-time.Minute),
+				"Security-Switch": now.Add(-10 * time.Minute),
+			}, stored: 10},
+			want: []Alert{{Rule: RuleServiceSilent, Service: "Security-Switch", Value: 600, Threshold: 300, FiredAt: now}},
+		},
+		{
+			name:   "rejection rate below threshold",
+			source: fakeSource{stored: 6, rejected: 4},
+		},
+		{
+			name:   "rejection rate at threshold",
+			source: fakeSource{stored: 5, rejected: 5},
+			want:   []Alert{{Rule: RuleRejectionRate, Value: 0.5, Threshold: 0.5, FiredAt: now}},
+		},
+		{
+			name:   "partial rejection above threshold fires",
+			source: fakeSource{stored: 4, rejected: 6},
+			want:   []Alert{{Rule: RuleRejectionRate, Value: 0.6, Threshold: 0.5, FiredAt: now}},
+		},
+		{
+			name:   "every message rejected fires",
+			source: fakeSource{rejected: 10},
+			want:   []Alert{{Rule: RuleRejectionRate, Value: 1, Threshold: 0.5, FiredAt: now}},
+		},
+		{
+			name:   "no messages at all is not a rejection rate",
+			source: fakeSource{},
+		},
+		{
+			name: "service error rate below threshold",
+			source: fakeSource{
+				requests: map[string]int64{"Entry-Hub": 100},
+				errors:   map[string]int64{"Entry-Hub": 9},
+			},
+		},
+		{
+			name: "service error rate above threshold marks only that service degraded",
+			source: fakeSource{
+				requests: map[string]int64{"Entry-Hub": 100, "Security-Switch": 100, "Database-Vault": 50},
+				errors:   map[string]int64{"Entry-Hub": 2, "Security-Switch": 25, "Database-Vault": 5},
+			},
+			want: []Alert{
+				{Rule: RuleServiceDegraded, Service: "Database-Vault", Value: 0.1, Threshold: 0.1, FiredAt: now},
+				{Rule: RuleServiceDegraded, Service: "Security-Switch", Value: 0.25, Threshold: 0.1, FiredAt: now},
+			},
+		},
+		{
+			name: "service with no requests has no error rate",
+			source: fakeSource{
+				requests: map[string]int64{"Entry-Hub": 0},
+				errors:   map[string]int64{"Entry-Hub": 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerter := &fakeAlerter{}
+			engine := newTestEngine(&tt.source, alerter)
+
+			if err := engine.Evaluate(context.Background(), now); err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if len(alerter.sent) != len(tt.want) {
+				t.Fatalf("sent %v, want %v", alerter.sent, tt.want)
+			}
+			for i := range tt.want {
+				if alerter.sent[i] != tt.want[i] {
+					t.Fatalf("sent[%d] = %+v, want %+v", i, alerter.sent[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// Requirement: MT-F-03
+func TestEngine_Evaluate_AlertsOncePerOccurrence(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	source := &fakeSource{lastSeen: map[string]time.Time{"Entry-Hub": now.Add(-10 * time.Minute)}}
+	alerter := &fakeAlerter{}
+	engine := newTestEngine(source, alerter)
+
+	// Still silent a minute later: no second alert.
+	for _, at := range []time.Time{now, now.Add(time.Minute)} {
+		if err := engine.Evaluate(context.Background(), at); err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+	}
+	if len(alerter.sent) != 1 {
+		t.Fatalf("sent %d alerts while the condition held, want 1", len(alerter.sent))
+	}
+
+	// The service recovers, then goes silent again: a new alert.
+	source.lastSeen["Entry-Hub"] = now.Add(2 * time.Minute)
+	if err := engine.Evaluate(context.Background(), now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if err := engine.Evaluate(context.Background(), now.Add(20*time.Minute)); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(alerter.sent) != 2 {
+		t.Fatalf("sent %d alerts after recovery and recurrence, want 2", len(alerter.sent))
+	}
+}
+
+// Requirement: MT-F-03
+func TestEngine_Evaluate_FailedSendIsRetried(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	source := &fakeSource{stored: 1, rejected: 9}
+	alerter := &fakeAlerter{err: errors.New("webhook down")}
+	engine := newTestEngine(source, alerter)
+
+	if err := engine.Evaluate(context.Background(), now); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	alerter.err = nil
+	if err := engine.Evaluate(context.Background(), now.Add(time.Minute)); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(alerter.sent) != 1 || alerter.sent[0].Rule != RuleRejectionRate {
+		t.Fatalf("sent %v, want the undelivered rejection-rate alert retried", alerter.sent)
+	}
+}
+
+// Requirement: MT-F-03
+func TestEngine_Evaluate_SourceErrorIsReturned(t *testing.T) {
+	errDB := errors.New("database unavailable")
+	engine := newTestEngine(&fakeSource{err: errDB}, &fakeAlerter{})
+
+	if err := engine.Evaluate(context.Background(), time.Now()); !errors.Is(err, errDB) {
+		t.Fatalf("Evaluate() error = %v, want wrapping %v", err, errDB)
+	}
+}
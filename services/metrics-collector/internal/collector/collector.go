@@ -30,6 +30,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -53,10 +54,54 @@ type Store interface {
 	Insert(ctx context.Context, payload metrics.Payload) error
 }
 
+// Quarantine is the optional persistence dependency Handler uses to record
+// a discarded message instead of only logging it. A real
+// internal/store.Store already satisfies this interface directly.
+type Quarantine interface {
+	InsertRejected(ctx context.Context, topic, reason string) error
+}
+
+// Discard reasons recorded by Quarantine.InsertRejected. A fixed set, so
+// a "rejected_metrics" row never carries free-form, publisher-influenced
+// text such as a JSON decoder error message.
+const (
+	reasonUnrecognizedTopic  = "unrecognized_topic"
+	reasonUnparseablePayload = "unparseable_payload"
+	reasonServiceMismatch    = "service_mismatch"
+	reasonClockSkew          = "clock_skew"
+)
+
 // Handler adapts an MQTT message arriving on any "metrics/<service>"
 // topic into a validated Store.Insert call.
 type Handler struct {
 	Store Store
+
+	// Quarantine, if non-nil, additionally records every discarded
+	// message's topic and discard reason ("quarantine" mode). If nil,
+	// discards are only logged ("drop" mode, the default) — see
+	// cmd/metrics-collector/main.go's envRejectionMode.
+	Quarantine Quarantine
+
+	// MaxFutureSkew, if positive, is how far ahead of this process's own
+	// clock a payload's timestamp may be before the payload is discarded
+	// as coming from a host with a drifting clock. Zero disables the
+	// check: every parseable timestamp is stored as-is, as before this
+	// field existed. See cmd/metrics-collector/main.go's envMaxFutureSkew.
+	MaxFutureSkew time.Duration
+
+	// Now returns the current time for the MaxFutureSkew comparison. Nil
+	// means time.Now; tests set it to pin the clock.
+	Now func() time.Time
+
+	clockSkewRejections atomic.Int64
+}
+
+// ClockSkewRejections returns how many payloads Handle has discarded so
+// far for exceeding MaxFutureSkew. Each discard also logs the running
+// total as clock_skew_rejections_total, so a drifting host shows up in the
+// log even in "drop" mode, where nothing reaches rejected_metrics.
+func (h *Handler) ClockSkewRejections() int64 {
+	return h.clockSkewRejections.Load()
 }
 
 // ServiceFromTopic derives the metrics.Payload.Service value a message on
@@ -74,9 +119,11 @@ func ServiceFromTopic(topic string) (service string, ok bool) {
 // Handle parses rawPayload as a metrics.Payload and inserts it via Store,
 // after checking (MT-F-02) that the payload's own "service" field matches
 // the topic it actually arrived on. A topic that doesn't match
-// "metrics/<service>", a payload that fails to decode, or a service-field
-// mismatch are all discarded — not stored — and logged; Handle returns a
-// non-nil error only for a genuine Store failure, never for a discard,
+// "metrics/<service>", a payload that fails to decode, a service-field
+// mismatch, or (with MaxFutureSkew set) a timestamp too far in the future
+// are all discarded — not stored — and logged (and, if h.Quarantine is
+// set, recorded there); Handle returns a non-nil error
+// only for a genuine Store or Quarantine failure, never for a discard,
 // since a discard is Handle correctly doing its job (RD-04, fail-secure:
 // an untrustworthy payload is dropped, not stored under a best guess).
 func (h *Handler) Handle(ctx context.Context, topic string, rawPayload []byte) error {
@@ -84,7 +131,7 @@ func (h *Handler) Handle(ctx context.Context, topic string, rawPayload []byte) e
 	if !ok {
 		slog.Warn("metrics-collector: discarding message on unrecognized topic",
 			"topic", logging.Sanitize(topic))
-		return nil
+		return h.quarantine(ctx, topic, reasonUnrecognizedTopic)
 	}
 
 	decoder := json.NewDecoder(bytes.NewReader(rawPayload))
@@ -93,13 +140,21 @@ func (h *Handler) Handle(ctx context.Context, topic string, rawPayload []byte) e
 	if err := decoder.Decode(&payload); err != nil {
 		slog.Warn("metrics-collector: discarding message with unparseable payload",
 			"topic", logging.Sanitize(topic), "error", logging.Sanitize(err.Error()))
-		return nil
+		return h.quarantine(ctx, topic, reasonUnparseablePayload)
 	}
 
 	if payload.Service != expectedService {
 		slog.Warn("metrics-collector: discarding message whose payload service does not match its topic",
 			"topic", logging.Sanitize(topic), "payload_service", logging.Sanitize(payload.Service))
-		return nil
+		return h.quarantine(ctx, topic, reasonServiceMismatch)
+	}
+
+	if h.beyondFutureSkew(payload) {
+		total := h.clockSkewRejections.Add(1)
+		slog.Warn("metrics-collector: discarding message timestamped too far in the future",
+			"topic", logging.Sanitize(topic), "timestamp", logging.Sanitize(payload.Timestamp),
+			"max_future_skew", h.MaxFutureSkew, "clock_skew_rejections_total", total)
+		return h.quarantine(ctx, topic, reasonClockSkew)
 	}
 
 	if err := h.Store.Insert(ctx, payload); err != nil {
@@ -109,6 +164,39 @@ func (h *Handler) Handle(ctx context.Context, topic string, rawPayload []byte) e
 	return nil
 }
 
+// beyondFutureSkew reports whether payload's timestamp is more than
+// h.MaxFutureSkew ahead of now. A timestamp that does not parse is left
+// for Store.Insert to reject, exactly as when the check is disabled.
+func (h *Handler) beyondFutureSkew(payload metrics.Payload) bool {
+	if h.MaxFutureSkew <= 0 {
+		return false
+	}
+	timestamp, err := time.Parse(time.RFC3339, payload.Timestamp)
+	if err != nil {
+		return false
+	}
+	now := time.Now
+	if h.Now != nil {
+		now = h.Now
+	}
+	return timestamp.After(now().Add(h.MaxFutureSkew))
+}
+
+// quarantine records an already-logged discard via h.Quarantine, or does
+// nothing in "drop" mode. A failure to record it is returned like any
+// other Store failure: the message itself is still discarded either way.
+func (h *Handler) quarantine(ctx context.Context, topic, reason string) error {
+	if h.Quarantine == nil {
+		return nil
+	}
+
+	if err := h.Quarantine.InsertRejected(ctx, logging.Sanitize(topic), reason); err != nil {
+		return fmt.Errorf("collector: quarantine discarded message: %w", err)
+	}
+
+	return nil
+}
+
 // OnMessage adapts Handle to paho's mqtt.MessageHandler signature (a
 // fixed (mqtt.Client, mqtt.Message) callback with no context parameter of
 // its own), the value passed to mqtt.Client.Subscribe in
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Verryx-02/RAM-USB/pkg/metrics"
 )
@@ -21,6 +22,22 @@ func (f *fakeStore) Insert(_ context.Context, payload metrics.Payload) error {
 	return f.insertErr
 }
 
+// fakeQuarantine is a hand-written fake of Quarantine (CONTRIBUTING.md
+// §7.5).
+type fakeQuarantine struct {
+	insertErr   error
+	insertCalls int
+	lastTopic   string
+	lastReason  string
+}
+
+func (f *fakeQuarantine) InsertRejected(_ context.Context, topic, reason string) error {
+	f.insertCalls++
+	f.lastTopic = topic
+	f.lastReason = reason
+	return f.insertErr
+}
+
 // Requirement: MT-F-01
 func TestServiceFromTopic(t *testing.T) {
 	tests := []struct {
@@ -85,6 +102,23 @@ func TestHandler_Handle(t *testing.T) {
 		}
 	})
 
+	t.Run("payload with an empty or missing service is discarded, not inserted", func(t *testing.T) {
+		for _, payload := range []string{
+			`{"service":"","timestamp":"2026-07-21T12:00:00Z","request_count":10,"error_count":0,"average_response_time_ms":5.5,"active_connections":2}`,
+			`{"timestamp":"2026-07-21T12:00:00Z","request_count":10,"error_count":0,"average_response_time_ms":5.5,"active_connections":2}`,
+		} {
+			fake := &fakeStore{}
+			h := &Handler{Store: fake}
+
+			if err := h.Handle(context.Background(), "metrics/Entry-Hub", []byte(payload)); err != nil {
+				t.Fatalf("Handle() error = %v, want nil", err)
+			}
+			if fake.insertCalls != 0 {
+				t.Fatalf("Insert called %d times for %s, want 0 (a nameless row must never be stored)", fake.insertCalls, payload)
+			}
+		}
+	})
+
 	t.Run("unrecognized topic is discarded, not inserted", func(t *testing.T) {
 		fake := &fakeStore{}
 		h := &Handler{Store: fake}
@@ -135,6 +169,116 @@ func TestHandler_Handle(t *testing.T) {
 	})
 }
 
+// Requirement: MT-F-02
+func TestHandler_Handle_QuarantineMode(t *testing.T) {
+	validPayload := `{"service":"Entry-Hub","timestamp":"2026-07-21T12:00:00Z","request_count":10,"error_count":0,"average_response_time_ms":5.5,"active_connections":2}`
+	mismatched := `{"service":"Database-Vault","timestamp":"2026-07-21T12:00:00Z","request_count":10,"error_count":0,"average_response_time_ms":5.5,"active_connections":2}`
+
+	tests := []struct {
+		name       string
+		topic      string
+		payload    string
+		wantReason string
+	}{
+		{name: "unrecognized topic", topic: "other/Entry-Hub", payload: validPayload, wantReason: reasonUnrecognizedTopic},
+		{name: "unparseable payload", topic: "metrics/Entry-Hub", payload: "{not json", wantReason: reasonUnparseablePayload},
+		{name: "service mismatch", topic: "metrics/Entry-Hub", payload: mismatched, wantReason: reasonServiceMismatch},
+		{name: "empty service", topic: "metrics/Entry-Hub", payload: `{"service":"","timestamp":"2026-07-21T12:00:00Z","request_count":10,"error_count":0,"average_response_time_ms":5.5,"active_connections":2}`, wantReason: reasonServiceMismatch},
+		{name: "empty topic service", topic: "metrics/", payload: validPayload, wantReason: reasonUnrecognizedTopic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" is quarantined, not inserted", func(t *testing.T) {
+			fake := &fakeStore{}
+			quarantine := &fakeQuarantine{}
+			h := &Handler{Store: fake, Quarantine: quarantine}
+
+			if err := h.Handle(context.Background(), tt.topic, []byte(tt.payload)); err != nil {
+				t.Fatalf("Handle() error = %v, want nil", err)
+			}
+			if fake.insertCalls != 0 {
+				t.Fatalf("Insert called %d times, want 0", fake.insertCalls)
+			}
+			if quarantine.insertCalls != 1 {
+				t.Fatalf("InsertRejected called %d times, want 1", quarantine.insertCalls)
+			}
+			if quarantine.lastTopic != tt.topic || quarantine.lastReason != tt.wantReason {
+				t.Fatalf("InsertRejected(%q, %q), want (%q, %q)",
+					quarantine.lastTopic, quarantine.lastReason, tt.topic, tt.wantReason)
+			}
+		})
+	}
+
+	t.Run("accepted payload is not quarantined", func(t *testing.T) {
+		fake := &fakeStore{}
+		quarantine := &fakeQuarantine{}
+		h := &Handler{Store: fake, Quarantine: quarantine}
+
+		if err := h.Handle(context.Background(), "metrics/Entry-Hub", []byte(validPayload)); err != nil {
+			t.Fatalf("Handle() error = %v, want nil", err)
+		}
+		if fake.insertCalls != 1 || quarantine.insertCalls != 0 {
+			t.Fatalf("Insert/InsertRejected called %d/%d times, want 1/0", fake.insertCalls, quarantine.insertCalls)
+		}
+	})
+
+	t.Run("Quarantine failure is propagated", func(t *testing.T) {
+		wantErr := errors.New("connection refused")
+		h := &Handler{Store: &fakeStore{}, Quarantine: &fakeQuarantine{insertErr: wantErr}}
+
+		err := h.Handle(context.Background(), "metrics/Entry-Hub", []byte("{not json"))
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Handle() error = %v, want wrapping %v", err, wantErr)
+		}
+	})
+}
+
+// Requirement: MT-F-02
+func TestHandler_Handle_MaxFutureSkew(t *testing.T) {
+	now := time.Date(2026, 7, 21, 12, 0, 0, 0, time.UTC)
+	payloadAt := func(ts time.Time) []byte {
+		return []byte(`{"service":"Entry-Hub","timestamp":"` + ts.Format(time.RFC3339) + `","request_count":1,"error_count":0,"average_response_time_ms":1,"active_connections":0}`)
+	}
+
+	tests := []struct {
+		name          string
+		maxFutureSkew time.Duration
+		timestamp     time.Time
+		wantStored    bool
+	}{
+		{name: "past timestamp", maxFutureSkew: 2 * time.Minute, timestamp: now.Add(-time.Hour), wantStored: true},
+		{name: "just inside the window", maxFutureSkew: 2 * time.Minute, timestamp: now.Add(2 * time.Minute), wantStored: true},
+		{name: "just outside the window", maxFutureSkew: 2 * time.Minute, timestamp: now.Add(2*time.Minute + time.Second), wantStored: false},
+		{name: "check disabled", maxFutureSkew: 0, timestamp: now.Add(24 * time.Hour), wantStored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{}
+			quarantine := &fakeQuarantine{}
+			h := &Handler{Store: store, Quarantine: quarantine, MaxFutureSkew: tt.maxFutureSkew, Now: func() time.Time { return now }}
+
+			if err := h.Handle(context.Background(), "metrics/Entry-Hub", payloadAt(tt.timestamp)); err != nil {
+				t.Fatalf("Handle() error = %v, want nil", err)
+			}
+
+			if stored := store.insertCalls == 1; stored != tt.wantStored {
+				t.Fatalf("stored = %v, want %v", stored, tt.wantStored)
+			}
+			wantRejections := int64(0)
+			if !tt.wantStored {
+				wantRejections = 1
+				if quarantine.lastReason != reasonClockSkew {
+					t.Fatalf("quarantine reason = %q, want %q", quarantine.lastReason, reasonClockSkew)
+				}
+			}
+			if got := h.ClockSkewRejections(); got != wantRejections {
+				t.Fatalf("ClockSkewRejections() = %d, want %d", got, wantRejections)
+			}
+		})
+	}
+}
+
 // Requirement: MT-F-02
 func TestHandler_OnMessage(t *testing.T) {
 	validPayload := `{"service":"Entry-Hub","timestamp":"2026-07-21T12:00:00Z","request_count":10,"error_count":0,"average_response_time_ms":5.5,"active_connections":2}`
@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/Verryx-02/RAM-USB/pkg/logging"
+)
+
+// ErrQueueFull means Queue.Enqueue found no free space in the queue; the
+// message is discarded rather than blocking the MQTT callback.
+var ErrQueueFull = errors.New("collector: message queue is full")
+
+// TableMissingAlertKey is the "alert" attribute value on the log line a
+// Queue writes when an insert first finds its table dropped
+// (isTableMissing), so log-based alerting can match it without parsing
+// the message, same convention as pkg/metrics.FailureAlertKey.
+const TableMissingAlertKey = "metrics_table_missing"
+
+// isTableMissing reports whether err, or an error it wraps, has a
+// TableMissing method reporting true: a Store's way of saying its table
+// does not exist, which internal/store.ErrTableMissing implements.
+func isTableMissing(err error) bool {
+	var missing interface{ TableMissing() bool }
+	return errors.As(err, &missing) && missing.TableMissing()
+}
+
+// message is one MQTT message copied off paho's callback goroutine.
+type message struct {
+	topic   string
+	payload []byte
+}
+
+// Queue moves Handler.Handle off paho's message-callback goroutine onto a
+// fixed pool of workers, so a slow or unavailable TimescaleDB cannot stall
+// MQTT message delivery. paho runs subscription callbacks one at a time,
+// in order (its default OrderMatters behavior), so Handler.OnMessage
+// blocking for up to insertTimeout per message backs up every message
+// behind it; Queue.OnMessage only copies the message into a bounded
+// channel and returns.
+//
+// Tradeoff: paho acknowledges a QoS 1 message once its callback returns,
+// which is now before the message is stored. A message still queued when
+// this process stops, or dropped because the queue is full, is therefore
+// lost rather than redelivered by the broker. For a once-a-minute metrics
+// snapshot (the next one supersedes it) that is preferable to letting a
+// database outage stall the subscription.
+type Queue struct {
+	handler  *Handler
+	messages chan message
+	workers  int
+	retries  int
+	backoff  time.Duration
+
+	// tableMissing is set while inserts fail with isTableMissing errors,
+	// so the alert is logged once per episode rather than per message.
+	tableMissing atomic.Bool
+}
+
+// NewQueue returns a Queue feeding handler through a buffer of size
+// messages and workers concurrent workers. A message whose Handle call
+// fails (a genuine Store failure, never a discard) is retried up to
+// retries more times, waiting backoff between attempts.
+func NewQueue(handler *Handler, size, workers, retries int, backoff time.Duration) (*Queue, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("collector: queue size must be positive, got %d", size)
+	}
+	if workers <= 0 {
+		return nil, fmt.Errorf("collector: queue workers must be positive, got %d", workers)
+	}
+	if retries < 0 {
+		return nil, fmt.Errorf("collector: queue retries must not be negative, got %d", retries)
+	}
+	if backoff < 0 {
+		return nil, fmt.Errorf("collector: queue retry backoff must not be negative, got %s", backoff)
+	}
+
+	return &Queue{
+		handler:  handler,
+		messages: make(chan message, size),
+		workers:  workers,
+		retries:  retries,
+		backoff:  backoff,
+	}, nil
+}
+
+// Enqueue copies topic and payload into the queue without blocking,
+// returning ErrQueueFull if there is no room.
+func (q *Queue) Enqueue(topic string, payload []byte) error {
+	msg := message{topic: topic, payload: append([]byte(nil), payload...)}
+
+	select {
+	case q.messages <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// OnMessage adapts Enqueue to paho's mqtt.MessageHandler signature, the
+// value passed to mqtt.Client.Subscribe in cmd/metrics-collector/main.go
+// in place of Handler.OnMessage.
+func (q *Queue) OnMessage(_ mqtt.Client, msg mqtt.Message) {
+	if err := q.Enqueue(msg.Topic(), msg.Payload()); err != nil {
+		slog.Error("metrics-collector: dropping message",
+			"topic", logging.Sanitize(msg.Topic()), "error", err)
+	}
+}
+
+// Run starts q's workers and blocks until ctx is done and every worker
+// has returned. Messages still queued at that point are not processed.
+func (q *Queue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range q.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work processes queued messages until ctx is done.
+func (q *Queue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-q.messages:
+			q.report(msg, q.handle(ctx, msg))
+		}
+	}
+}
+
+// report logs the outcome of handling msg. An isTableMissing failure is
+// logged once, with "alert"=TableMissingAlertKey, when the first message
+// finds the table gone; the messages dropped after it are not logged
+// individually, and the next message handled without error ends the
+// episode with an info line.
+func (q *Queue) report(msg message, err error) {
+	switch {
+	case isTableMissing(err):
+		if q.tableMissing.CompareAndSwap(false, true) {
+			slog.Error("metrics-collector: table missing, dropping messages until it is restored",
+				"alert", TableMissingAlertKey, "topic", logging.Sanitize(msg.topic), "error", logging.Sanitize(err.Error()))
+		}
+	case err != nil:
+		slog.Error("metrics-collector: handle message failed",
+			"topic", logging.Sanitize(msg.topic), "error", logging.Sanitize(err.Error()))
+	default:
+		if q.tableMissing.CompareAndSwap(true, false) {
+			slog.Info("metrics-collector: messages are being handled again after a missing table")
+		}
+	}
+}
+
+// handle runs Handler.Handle for msg, each attempt bounded by
+// insertTimeout, retrying a failure up to q.retries times. An
+// isTableMissing failure is returned at once: no retry can succeed until
+// an operator recreates the table.
+func (q *Queue) handle(ctx context.Context, msg message) error {
+	var err error
+	for attempt := 0; attempt <= q.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w (gave up retrying: %w)", err, ctx.Err())
+			case <-time.After(q.backoff):
+			}
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, insertTimeout)
+		err = q.handler.Handle(attemptCtx, msg.topic, msg.payload)
+		cancel()
+		if err == nil || isTableMissing(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w (after %d attempts)", err, q.retries+1)
+}
@@ -0,0 +1,290 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/metrics"
+)
+
+// blockingStore is a hand-written fake of Store (CONTRIBUTING.md §7.5)
+// whose Insert blocks until release is closed, standing in for a slow or
+// unavailable TimescaleDB.
+type blockingStore struct {
+	release chan struct{}
+
+	mu       sync.Mutex
+	inserted []metrics.Payload
+}
+
+func (b *blockingStore) Insert(ctx context.Context, payload metrics.Payload) error {
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inserted = append(b.inserted, payload)
+	return nil
+}
+
+func (b *blockingStore) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.inserted)
+}
+
+// flakyStore is a hand-written fake of Store (CONTRIBUTING.md §7.5) that
+// fails its first failures Insert calls, then succeeds.
+type flakyStore struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (f *flakyStore) Insert(_ context.Context, _ metrics.Payload) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (f *flakyStore) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// errTableMissing stands in for internal/store.ErrTableMissing: any error
+// with a TableMissing method reporting true.
+type errTableMissing struct{}
+
+func (errTableMissing) Error() string      { return "table does not exist" }
+func (errTableMissing) TableMissing() bool { return true }
+
+// droppedTableStore is a hand-written fake of Store (CONTRIBUTING.md
+// §7.5) whose Insert fails the way store.Store.Insert does once the
+// "metrics" table has been dropped, until restored is set.
+type droppedTableStore struct {
+	mu       sync.Mutex
+	restored bool
+	calls    int
+}
+
+func (d *droppedTableStore) Insert(_ context.Context, _ metrics.Payload) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls++
+	if !d.restored {
+		return fmt.Errorf("store: insert metrics row: %w: relation \"metrics\" does not exist", errTableMissing{})
+	}
+	return nil
+}
+
+const queueTestPayload = `{"service":"Entry-Hub","timestamp":"2026-07-21T12:00:00Z","request_count":10,"error_count":0,"average_response_time_ms":5.5,"active_connections":2}`
+
+// waitFor polls cond until it holds or a generous deadline passes.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// Requirement: MT-F-03
+func TestNewQueue_RejectsInvalidConfig(t *testing.T) {
+	h := &Handler{Store: &fakeStore{}}
+
+	tests := []struct {
+		name    string
+		size    int
+		workers int
+		retries int
+		backoff time.Duration
+	}{
+		{name: "zero size", size: 0, workers: 1},
+		{name: "zero workers", size: 1, workers: 0},
+		{name: "negative retries", size: 1, workers: 1, retries: -1},
+		{name: "negative backoff", size: 1, workers: 1, backoff: -time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewQueue(h, tt.size, tt.workers, tt.retries, tt.backoff); err == nil {
+				t.Fatal("NewQueue() error = nil, want non-nil")
+			}
+		})
+	}
+}
+
+// Requirement: MT-F-03
+func TestQueue_OnMessageReturnsWhileStorageIsSlow(t *testing.T) {
+	store := &blockingStore{release: make(chan struct{})}
+	q, err := NewQueue(&Handler{Store: store}, 10, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	returned := make(chan struct{})
+	go func() {
+		for range 3 {
+			q.OnMessage(nil, fakeMessage{topic: "metrics/Entry-Hub", payload: []byte(queueTestPayload)})
+		}
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("OnMessage blocked on a stalled Store, want it to return immediately")
+	}
+
+	if got := store.count(); got != 0 {
+		t.Fatalf("inserted %d payloads before the Store was released, want 0", got)
+	}
+
+	close(store.release)
+	waitFor(t, func() bool { return store.count() == 3 })
+}
+
+// Requirement: MT-F-03
+func TestQueue_EnqueueWhenFull(t *testing.T) {
+	q, err := NewQueue(&Handler{Store: &fakeStore{}}, 1, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v, want nil", err)
+	}
+
+	// Run is never started, so nothing drains the one-slot queue.
+	if err := q.Enqueue("metrics/Entry-Hub", []byte(queueTestPayload)); err != nil {
+		t.Fatalf("first Enqueue() error = %v, want nil", err)
+	}
+	if err := q.Enqueue("metrics/Entry-Hub", []byte(queueTestPayload)); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("second Enqueue() error = %v, want ErrQueueFull", err)
+	}
+}
+
+// Requirement: MT-F-03
+func TestQueue_RetriesStoreFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		retries   int
+		wantCalls int
+	}{
+		{name: "succeeds within the retry budget", failures: 2, retries: 2, wantCalls: 3},
+		{name: "gives up once the retry budget is spent", failures: 5, retries: 1, wantCalls: 2},
+		{name: "no retries configured", failures: 5, retries: 0, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &flakyStore{failures: tt.failures}
+			q, err := NewQueue(&Handler{Store: store}, 1, 1, tt.retries, time.Millisecond)
+			if err != nil {
+				t.Fatalf("NewQueue() error = %v, want nil", err)
+			}
+
+			err = q.handle(context.Background(), message{topic: "metrics/Entry-Hub", payload: []byte(queueTestPayload)})
+			wantErr := tt.failures >= tt.wantCalls
+			if (err != nil) != wantErr {
+				t.Fatalf("handle() error = %v, wantErr %v", err, wantErr)
+			}
+			if got := store.callCount(); got != tt.wantCalls {
+				t.Fatalf("Insert called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// Requirement: MT-F-02
+func TestQueue_DiscardIsNotRetried(t *testing.T) {
+	store := &flakyStore{}
+	q, err := NewQueue(&Handler{Store: store}, 1, 1, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v, want nil", err)
+	}
+
+	if err := q.handle(context.Background(), message{topic: "metrics/Entry-Hub", payload: []byte("{not json")}); err != nil {
+		t.Fatalf("handle() error = %v, want nil (a discard is not an error)", err)
+	}
+	if got := store.callCount(); got != 0 {
+		t.Fatalf("Insert called %d times, want 0", got)
+	}
+}
+
+// Requirement: MT-F-03
+func TestQueue_MissingTableIsNotRetried(t *testing.T) {
+	dropped := &droppedTableStore{}
+	q, err := NewQueue(&Handler{Store: dropped}, 1, 1, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v, want nil", err)
+	}
+
+	err = q.handle(context.Background(), message{topic: "metrics/Entry-Hub", payload: []byte(queueTestPayload)})
+	if !isTableMissing(err) {
+		t.Fatalf("handle() error = %v, want a table-missing error", err)
+	}
+	if dropped.calls != 1 {
+		t.Fatalf("Insert called %d times, want 1 (a missing table must not burn retries)", dropped.calls)
+	}
+}
+
+// Requirement: MT-F-03
+func TestQueue_MissingTableAlertsOncePerEpisode(t *testing.T) {
+	var logBuf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logBuf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	dropped := &droppedTableStore{}
+	q, err := NewQueue(&Handler{Store: dropped}, 1, 1, 0, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewQueue() error = %v, want nil", err)
+	}
+	msg := message{topic: "metrics/Entry-Hub", payload: []byte(queueTestPayload)}
+
+	for range 3 {
+		q.report(msg, q.handle(context.Background(), msg))
+	}
+	if got := strings.Count(logBuf.String(), "alert="+TableMissingAlertKey); got != 1 {
+		t.Fatalf("logged %d alerts for three messages, want 1:\n%s", got, logBuf.String())
+	}
+
+	dropped.restored = true
+	q.report(msg, q.handle(context.Background(), msg))
+	if !strings.Contains(logBuf.String(), "handled again") {
+		t.Fatalf("no recovery line logged after the table was restored:\n%s", logBuf.String())
+	}
+
+	dropped.restored = false
+	q.report(msg, q.handle(context.Background(), msg))
+	if got := strings.Count(logBuf.String(), "alert="+TableMissingAlertKey); got != 2 {
+		t.Fatalf("logged %d alerts after a second drop, want 2", got)
+	}
+}
@@ -24,15 +24,19 @@
 package schema
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	migratepgx5 "github.com/golang-migrate/migrate/v4/database/pgx/v5"
 	_ "github.com/golang-migrate/migrate/v4/source/file" // registers the "file://" migration-source driver New's migrationsDir path needs
 
 	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver sql.Open above needs
+
+	"github.com/Verryx-02/RAM-USB/pkg/pgpool"
 )
 
 // New builds a *migrate.Migrate pointed at the migrations in
@@ -48,6 +52,10 @@ func New(databaseURL, migrationsDir string) (*migrate.Migrate, error) {
 
 	driver, err := migratepgx5.WithInstance(sqlDB, &migratepgx5.Config{})
 	if err != nil {
+		// Close the pool sql.Open created: NewWithRetry calls New
+		// repeatedly while the database is unreachable, and each failed
+		// attempt would otherwise leak one.
+		_ = sqlDB.Close()
 		return nil, fmt.Errorf("schema: migratepgx5.WithInstance: %w", err)
 	}
 
@@ -59,6 +67,21 @@ func New(databaseURL, migrationsDir string) (*migrate.Migrate, error) {
 	return m, nil
 }
 
+// NewWithRetry calls New until it succeeds, through pgpool.Retry, for up
+// to timeout, so startup survives a database that is still starting.
+func NewWithRetry(ctx context.Context, databaseURL, migrationsDir string, timeout time.Duration) (*migrate.Migrate, error) {
+	var m *migrate.Migrate
+	err := pgpool.Retry(ctx, timeout, func() error {
+		var err error
+		m, err = New(databaseURL, migrationsDir)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Apply runs every pending migration, bringing the schema up to date.
 // migrate.ErrNoChange (schema already current) is treated as success, not
 // an error. Apply only ever calls m.Up() — Down() is test-cleanup-only and
@@ -9,9 +9,11 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -20,12 +22,91 @@ import (
 
 // insertMetricsSQL matches the "metrics" table's column shape exactly, as
 // documented in
-// services/metrics-collector/migrations/000001_create_metrics_table.up.sql.
+// services/metrics-collector/migrations/000001_create_metrics_table.up.sql
+// and the columns every later migration but 000002 and 000005 adds to it.
 const insertMetricsSQL = `
 INSERT INTO metrics (
 	time, service, request_count, error_count,
-	average_response_time_ms, active_connections
-) VALUES ($1, $2, $3, $4, $5, $6)`
+	average_response_time_ms, active_connections,
+	dry_run_count, dry_run_error_count,
+	mx_lookup_count, mx_rejection_count, mx_lookup_failure_count,
+	db_pool_acquired, db_pool_idle, db_pool_max, db_pool_wait_duration_ms,
+	hashes_in_flight, average_encryption_time_ms, db_pool_leak_suspected
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
+// insertRejectedSQL matches the "rejected_metrics" table's column shape,
+// as documented in
+// services/metrics-collector/migrations/000002_create_rejected_metrics_table.up.sql.
+const insertRejectedSQL = `
+INSERT INTO rejected_metrics (rejected_at, topic, reason)
+VALUES ($1, $2, $3)`
+
+// hypertableExistsSQL asks TimescaleDB's own catalog whether "metrics" in
+// the connection's current schema is a hypertable. It matches neither a
+// missing table nor a plain (non-hypertable) one, and fails outright if
+// the timescaledb extension itself is missing, since the
+// timescaledb_information schema then does not exist.
+const hypertableExistsSQL = `SELECT EXISTS (
+	SELECT 1 FROM timescaledb_information.hypertables
+	WHERE hypertable_schema = current_schema() AND hypertable_name = 'metrics'
+)`
+
+// ErrNotHypertable is returned by CheckHypertable when the "metrics" table
+// is missing or is a plain table rather than a TimescaleDB hypertable.
+var ErrNotHypertable = errors.New(`store: "metrics" is missing or is not a TimescaleDB hypertable`)
+
+// pgUndefinedTableCode is the PostgreSQL SQLSTATE for undefined_table
+// (42P01), documented at
+// https://www.postgresql.org/docs/current/errcodes-appendix.html. Same
+// single-constant approach as Database-Vault's pgUniqueViolationCode.
+const pgUndefinedTableCode = "42P01"
+
+// ErrTableMissing is returned by Insert and InsertRejected when their
+// table does not exist, which after CheckHypertable passed at startup
+// means someone dropped it while this process runs. Retrying cannot
+// succeed, and neither can re-running the migrations: golang-migrate's
+// schema_migrations table still records them as applied, so restoring the
+// table takes an operator (and a restart, for CheckHypertable to confirm
+// it is a hypertable again).
+//
+// Its TableMissing method is how internal/collector recognizes it
+// without importing this package.
+var ErrTableMissing error = tableMissingError{}
+
+// tableMissingError is ErrTableMissing's type.
+type tableMissingError struct{}
+
+func (tableMissingError) Error() string { return "store: table does not exist" }
+
+// TableMissing reports true, marking the error as internal/collector's
+// "table missing" failure.
+func (tableMissingError) TableMissing() bool { return true }
+
+// RowQuerier is the minimal subset of *pgxpool.Pool that CheckHypertable
+// needs, kept separate from Querier so Insert's own fakes need not grow a
+// QueryRow method they never use. A bare *pgxpool.Pool satisfies it
+// directly.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// CheckHypertable verifies "metrics" exists and is a TimescaleDB
+// hypertable (MT-F-03). Inserts into a plain table would succeed, but
+// silently without MT-F-03's partitioning, retention, and compression;
+// a missing table would fail every insert. Either way the collector
+// would otherwise only find out one message at a time, so
+// cmd/metrics-collector/main.go calls this once at startup and refuses
+// to start (RD-04) instead.
+func CheckHypertable(ctx context.Context, db RowQuerier) error {
+	var exists bool
+	if err := db.QueryRow(ctx, hypertableExistsSQL).Scan(&exists); err != nil {
+		return fmt.Errorf("store: query hypertable catalog: %w", err)
+	}
+	if !exists {
+		return ErrNotHypertable
+	}
+	return nil
+}
 
 // Querier is the minimal subset of *pgxpool.Pool that Insert needs.
 // Depending on this narrow interface, instead of the full pgxpool.Pool
@@ -76,9 +157,236 @@ func (s Store) Insert(ctx context.Context, payload metrics.Payload) error {
 		payload.ErrorCount,
 		payload.AverageResponseTimeMs,
 		payload.ActiveConnections,
+		payload.DryRunCount,
+		payload.DryRunErrorCount,
+		payload.MXLookupCount,
+		payload.MXRejectionCount,
+		payload.MXLookupFailureCount,
+		payload.DBPoolAcquired,
+		payload.DBPoolIdle,
+		payload.DBPoolMax,
+		payload.DBPoolWaitDurationMs,
+		payload.HashesInFlight,
+		payload.AverageEncryptionTimeMs,
+		payload.DBPoolLeakSuspected,
 	); err != nil {
-		return fmt.Errorf("store: insert metrics row: %w", err)
+		return classifyExecError("insert metrics row", err)
 	}
 
 	return nil
 }
+
+// InsertRejected records that a message on topic was discarded for reason
+// in the "rejected_metrics" quarantine table. It never receives the
+// rejected payload itself — see that table's migration for why nothing
+// but the topic and reason is kept. topic is expected to already be
+// sanitized by the caller (internal/collector.Handler does so via
+// logging.Sanitize, the same as for its own log lines).
+func (s Store) InsertRejected(ctx context.Context, topic, reason string) error {
+	if _, err := s.DB.Exec(ctx, insertRejectedSQL, time.Now().UTC(), topic, reason); err != nil {
+		return classifyExecError("insert rejected metrics row", err)
+	}
+
+	return nil
+}
+
+// classifyExecError wraps a failed Exec's err, described by action, in
+// ErrTableMissing if PostgreSQL reported that the table does not exist, so
+// internal/collector.Queue can tell it apart from a failure worth
+// retrying.
+func classifyExecError(action string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUndefinedTableCode {
+		return fmt.Errorf("store: %s: %w: %w", action, ErrTableMissing, err)
+	}
+	return fmt.Errorf("store: %s: %w", action, err)
+}
+
+// lastSeenSQL returns each service's newest stored row time. TimescaleDB
+// answers max(time) per service from each chunk's newest rows, and the
+// 30-day retention policy (MT-F-03) bounds how many chunks exist.
+const lastSeenSQL = `SELECT service, max(time) FROM metrics GROUP BY service`
+
+// countsSQL counts stored and rejected messages since $1.
+const countsSQL = `SELECT
+	(SELECT count(*) FROM metrics WHERE time >= $1),
+	(SELECT count(*) FROM rejected_metrics WHERE rejected_at >= $1)`
+
+// errorCountsSQL returns how much each service's request_count and
+// error_count grew across its rows since $1. Publishers report running
+// totals that never reset, so summing the rows themselves would weight
+// the window by the service's whole lifetime. Instead each row
+// contributes its increase over the service's previous row in the
+// window; a row where either counter dropped follows a publisher restart,
+// which starts both totals again from zero, so it contributes its values
+// as they are. The first row in the window only serves as the baseline.
+// sum(bigint) is numeric in PostgreSQL, hence the casts back.
+const errorCountsSQL = `SELECT service,
+	sum(CASE WHEN restarted THEN request_count ELSE request_count - prev_request_count END)::bigint,
+	sum(CASE WHEN restarted THEN error_count ELSE error_count - prev_error_count END)::bigint
+FROM (
+	SELECT service, request_count, error_count, prev_request_count, prev_error_count,
+		request_count < prev_request_count OR error_count < prev_error_count AS restarted
+	FROM (
+		SELECT service, request_count, error_count,
+			lag(request_count) OVER w AS prev_request_count,
+			lag(error_count) OVER w AS prev_error_count
+		FROM metrics WHERE time >= $1
+		WINDOW w AS (PARTITION BY service ORDER BY time)
+	) AS samples
+	WHERE prev_request_count IS NOT NULL
+) AS increases
+GROUP BY service`
+
+// countByServiceSQL counts stored metrics rows per service in [$1, $2).
+const countByServiceSQL = `SELECT service, count(*) FROM metrics WHERE time >= $1 AND time < $2 GROUP BY service`
+
+// latestSQL returns one service's newest stored row. The hypertable's
+// default time index and its columnstore segmentby = 'service' setting
+// let TimescaleDB answer it without scanning the service's history.
+const latestSQL = `SELECT time, request_count, error_count, average_response_time_ms, active_connections,
+	dry_run_count, dry_run_error_count, mx_lookup_count, mx_rejection_count, mx_lookup_failure_count,
+	db_pool_acquired, db_pool_idle, db_pool_max, db_pool_wait_duration_ms, hashes_in_flight,
+	average_encryption_time_ms, db_pool_leak_suspected
+FROM metrics WHERE service = $1 ORDER BY time DESC LIMIT 1`
+
+// ErrNoMetrics is returned by Reader.Latest when the service has no
+// stored row within the 30-day retention window (MT-F-03).
+var ErrNoMetrics = errors.New("store: no stored metrics for this service")
+
+// ReadQuerier is the minimal subset of *pgxpool.Pool that Reader needs.
+type ReadQuerier interface {
+	RowQuerier
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Reader answers internal/alert.Engine's questions about recently stored
+// metrics, implementing alert.Source, and operators' capacity-planning
+// question of how much each service sends (CountByService) and what it
+// last reported (Latest).
+type Reader struct {
+	DB ReadQuerier
+}
+
+// LastSeen returns each service's newest stored metrics row time.
+func (r Reader) LastSeen(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := r.DB.Query(ctx, lastSeenSQL)
+	if err != nil {
+		return nil, fmt.Errorf("store: query last-seen times: %w", err)
+	}
+	defer rows.Close()
+
+	lastSeen := make(map[string]time.Time)
+	for rows.Next() {
+		var service string
+		var seen time.Time
+		if err := rows.Scan(&service, &seen); err != nil {
+			return nil, fmt.Errorf("store: scan last-seen row: %w", err)
+		}
+		lastSeen[service] = seen
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: read last-seen rows: %w", err)
+	}
+	return lastSeen, nil
+}
+
+// Counts returns how many metrics rows were stored, and how many messages
+// were recorded in "rejected_metrics", since since.
+func (r Reader) Counts(ctx context.Context, since time.Time) (stored, rejected int64, err error) {
+	if err := r.DB.QueryRow(ctx, countsSQL, since).Scan(&stored, &rejected); err != nil {
+		return 0, 0, fmt.Errorf("store: count stored and rejected messages: %w", err)
+	}
+	return stored, rejected, nil
+}
+
+// ErrorCounts returns, per service, how many requests and errors it
+// reported handling since since: the growth of its running request_count
+// and error_count totals across the window (errorCountsSQL). A service
+// with fewer than two rows in the window is absent from both maps.
+func (r Reader) ErrorCounts(ctx context.Context, since time.Time) (requests, errs map[string]int64, err error) {
+	rows, err := r.DB.Query(ctx, errorCountsSQL, since)
+	if err != nil {
+		return nil, nil, fmt.Errorf("store: query per-service error counts: %w", err)
+	}
+	defer rows.Close()
+
+	requests = make(map[string]int64)
+	errs = make(map[string]int64)
+	for rows.Next() {
+		var service string
+		var requestCount, errorCount int64
+		if err := rows.Scan(&service, &requestCount, &errorCount); err != nil {
+			return nil, nil, fmt.Errorf("store: scan per-service error count row: %w", err)
+		}
+		requests[service] = requestCount
+		errs[service] = errorCount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("store: read per-service error count rows: %w", err)
+	}
+	return requests, errs, nil
+}
+
+// CountByService returns how many metrics rows each service stored with a
+// timestamp in [start, end). A service with no rows in the window is
+// absent from the map rather than present with zero: this table has no
+// list of expected services to fill in (see internal/alert's
+// RuleServiceSilent for the "should have sent but did not" question).
+func (r Reader) CountByService(ctx context.Context, start, end time.Time) (map[string]int64, error) {
+	rows, err := r.DB.Query(ctx, countByServiceSQL, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("store: query per-service counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var service string
+		var count int64
+		if err := rows.Scan(&service, &count); err != nil {
+			return nil, fmt.Errorf("store: scan per-service count row: %w", err)
+		}
+		counts[service] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("store: read per-service count rows: %w", err)
+	}
+	return counts, nil
+}
+
+// Latest returns service's newest stored metrics row as the
+// metrics.Payload it was stored from, with Timestamp in the RFC3339 form
+// metrics.BuildPayload uses. A service with no stored row returns an error
+// wrapping ErrNoMetrics.
+func (r Reader) Latest(ctx context.Context, service string) (metrics.Payload, error) {
+	var at time.Time
+	payload := metrics.Payload{Service: service}
+	err := r.DB.QueryRow(ctx, latestSQL, service).Scan(
+		&at,
+		&payload.RequestCount,
+		&payload.ErrorCount,
+		&payload.AverageResponseTimeMs,
+		&payload.ActiveConnections,
+		&payload.DryRunCount,
+		&payload.DryRunErrorCount,
+		&payload.MXLookupCount,
+		&payload.MXRejectionCount,
+		&payload.MXLookupFailureCount,
+		&payload.DBPoolAcquired,
+		&payload.DBPoolIdle,
+		&payload.DBPoolMax,
+		&payload.DBPoolWaitDurationMs,
+		&payload.HashesInFlight,
+		&payload.AverageEncryptionTimeMs,
+		&payload.DBPoolLeakSuspected,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return metrics.Payload{}, fmt.Errorf("%w", ErrNoMetrics)
+		}
+		return metrics.Payload{}, fmt.Errorf("store: query latest metrics row: %w", err)
+	}
+	payload.Timestamp = at.UTC().Format(time.RFC3339)
+	return payload, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -34,15 +35,54 @@ func (f *fakeQuerier) Exec(_ context.Context, sql string, arguments ...any) (pgc
 	return pgconn.NewCommandTag("INSERT 0 1"), nil
 }
 
+// fakeRow is a hand-written fake of pgx.Row (CONTRIBUTING.md §7.5),
+// scanning exists into the single bool CheckHypertable reads.
+type fakeRow struct {
+	exists bool
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*bool) = r.exists
+	return nil
+}
+
+// fakeRowQuerier is a hand-written fake of RowQuerier (CONTRIBUTING.md
+// §7.5).
+type fakeRowQuerier struct {
+	row     fakeRow
+	lastSQL string
+}
+
+func (f *fakeRowQuerier) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	f.lastSQL = sql
+	return f.row
+}
+
 // Requirement: MT-F-03
 func TestStore_Insert(t *testing.T) {
 	validPayload := metrics.Payload{
-		Service:               "Entry-Hub",
-		Timestamp:             "2026-07-21T12:00:00Z",
-		RequestCount:          42,
-		ErrorCount:            1,
-		AverageResponseTimeMs: 12.5,
-		ActiveConnections:     3,
+		Service:                 "Entry-Hub",
+		Timestamp:               "2026-07-21T12:00:00Z",
+		RequestCount:            42,
+		ErrorCount:              1,
+		AverageResponseTimeMs:   12.5,
+		ActiveConnections:       3,
+		DryRunCount:             9,
+		DryRunErrorCount:        4,
+		MXLookupCount:           20,
+		MXRejectionCount:        5,
+		MXLookupFailureCount:    1,
+		DBPoolAcquired:          2,
+		DBPoolIdle:              8,
+		DBPoolMax:               10,
+		DBPoolWaitDurationMs:    250,
+		HashesInFlight:          2,
+		AverageEncryptionTimeMs: 0.04,
+		DBPoolLeakSuspected:     true,
 	}
 
 	t.Run("valid payload is inserted with a parsed timestamp", func(t *testing.T) {
@@ -69,6 +109,21 @@ func TestStore_Insert(t *testing.T) {
 		if fake.lastArgs[1] != validPayload.Service {
 			t.Fatalf("second argument = %v, want %v", fake.lastArgs[1], validPayload.Service)
 		}
+		if len(fake.lastArgs) != 18 || fake.lastArgs[6] != validPayload.DryRunCount || fake.lastArgs[7] != validPayload.DryRunErrorCount {
+			t.Fatalf("arguments = %v, want the dry-run counts after active_connections", fake.lastArgs)
+		}
+		if fake.lastArgs[8] != validPayload.MXLookupCount || fake.lastArgs[9] != validPayload.MXRejectionCount || fake.lastArgs[10] != validPayload.MXLookupFailureCount {
+			t.Fatalf("arguments = %v, want the mx counts after the dry-run counts", fake.lastArgs)
+		}
+		if fake.lastArgs[11] != validPayload.DBPoolAcquired || fake.lastArgs[12] != validPayload.DBPoolIdle || fake.lastArgs[13] != validPayload.DBPoolMax || fake.lastArgs[14] != validPayload.DBPoolWaitDurationMs {
+			t.Fatalf("arguments = %v, want the db pool gauges after the mx counts", fake.lastArgs)
+		}
+		if fake.lastArgs[15] != validPayload.HashesInFlight || fake.lastArgs[16] != validPayload.AverageEncryptionTimeMs {
+			t.Fatalf("arguments = %v, want hashes_in_flight and the encryption time after the db pool gauges", fake.lastArgs)
+		}
+		if fake.lastArgs[17] != validPayload.DBPoolLeakSuspected {
+			t.Fatalf("arguments = %v, want the db pool leak flag last", fake.lastArgs)
+		}
 	})
 
 	t.Run("malformed timestamp is rejected before any Exec call", func(t *testing.T) {
@@ -96,9 +151,102 @@ func TestStore_Insert(t *testing.T) {
 		if !errors.Is(err, wantErr) {
 			t.Fatalf("Insert() error = %v, want wrapping %v", err, wantErr)
 		}
+		if errors.Is(err, ErrTableMissing) {
+			t.Fatalf("Insert() error = %v, want not ErrTableMissing", err)
+		}
+	})
+
+	t.Run("missing table is reported as ErrTableMissing", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: pgUndefinedTableCode, Message: `relation "metrics" does not exist`}
+		s := Store{DB: &fakeQuerier{execErr: pgErr}}
+
+		err := s.Insert(context.Background(), validPayload)
+		if !errors.Is(err, ErrTableMissing) {
+			t.Fatalf("Insert() error = %v, want ErrTableMissing", err)
+		}
+		if !errors.Is(err, pgErr) {
+			t.Fatalf("Insert() error = %v, want wrapping the original *pgconn.PgError", err)
+		}
+		// internal/collector matches on this method, not on ErrTableMissing.
+		var missing interface{ TableMissing() bool }
+		if !errors.As(err, &missing) || !missing.TableMissing() {
+			t.Fatalf("Insert() error = %v, want one reporting TableMissing() = true", err)
+		}
 	})
 }
 
+// Requirement: MT-F-02
+func TestStore_InsertRejected(t *testing.T) {
+	t.Run("topic and reason are inserted, nothing else", func(t *testing.T) {
+		fake := &fakeQuerier{}
+		s := Store{DB: fake}
+
+		if err := s.InsertRejected(context.Background(), "metrics/Entry-Hub", "service_mismatch"); err != nil {
+			t.Fatalf("InsertRejected() error = %v, want nil", err)
+		}
+		if fake.lastSQL != insertRejectedSQL {
+			t.Fatalf("Exec SQL = %q, want insertRejectedSQL", fake.lastSQL)
+		}
+		if len(fake.lastArgs) != 3 {
+			t.Fatalf("Exec called with %d arguments, want 3", len(fake.lastArgs))
+		}
+		if _, ok := fake.lastArgs[0].(time.Time); !ok {
+			t.Fatalf("first argument = %T, want time.Time", fake.lastArgs[0])
+		}
+		if fake.lastArgs[1] != "metrics/Entry-Hub" || fake.lastArgs[2] != "service_mismatch" {
+			t.Fatalf("arguments = %v, want topic and reason", fake.lastArgs[1:])
+		}
+	})
+
+	t.Run("Exec failure is propagated", func(t *testing.T) {
+		wantErr := errors.New("connection refused")
+		s := Store{DB: &fakeQuerier{execErr: wantErr}}
+
+		err := s.InsertRejected(context.Background(), "metrics/Entry-Hub", "service_mismatch")
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("InsertRejected() error = %v, want wrapping %v", err, wantErr)
+		}
+	})
+
+	t.Run("missing table is reported as ErrTableMissing", func(t *testing.T) {
+		s := Store{DB: &fakeQuerier{execErr: &pgconn.PgError{Code: pgUndefinedTableCode}}}
+
+		err := s.InsertRejected(context.Background(), "metrics/Entry-Hub", "service_mismatch")
+		if !errors.Is(err, ErrTableMissing) {
+			t.Fatalf("InsertRejected() error = %v, want ErrTableMissing", err)
+		}
+	})
+}
+
+// Requirement: MT-F-03
+func TestCheckHypertable(t *testing.T) {
+	errCatalog := errors.New(`relation "timescaledb_information.hypertables" does not exist`)
+
+	tests := []struct {
+		name    string
+		row     fakeRow
+		wantErr error
+	}{
+		{name: "hypertable present", row: fakeRow{exists: true}},
+		{name: "missing or plain table", row: fakeRow{exists: false}, wantErr: ErrNotHypertable},
+		{name: "timescaledb extension missing", row: fakeRow{err: errCatalog}, wantErr: errCatalog},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeRowQuerier{row: tt.row}
+
+			err := CheckHypertable(context.Background(), db)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckHypertable() error = %v, want %v", err, tt.wantErr)
+			}
+			if db.lastSQL != hypertableExistsSQL {
+				t.Fatalf("query = %q, want hypertableExistsSQL", db.lastSQL)
+			}
+		})
+	}
+}
+
 // databaseURLEnvVar names the environment variable that points this test
 // at a real TimescaleDB instance (e.g. the metrics-collector-timescaledb
 // service in deployments/compose/metrics-collector-timescaledb.yml). docs/Test_Plan.md §4
@@ -165,3 +313,397 @@ func TestStore_Insert_Postgres(t *testing.T) {
 		t.Fatalf("metrics rows for service %q = %d, want 1", payload.Service, count)
 	}
 }
+
+// Requirement: MT-F-03
+func TestCheckHypertable_Postgres(t *testing.T) {
+	databaseURL := os.Getenv(databaseURLEnvVar)
+	if databaseURL == "" {
+		t.Skipf("%s not set; skipping the real-TimescaleDB MT-F-03 test (see TestStore_Insert_Postgres).", databaseURLEnvVar)
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	// Before migrations run, "metrics" does not exist yet.
+	if err := CheckHypertable(ctx, pool); !errors.Is(err, ErrNotHypertable) {
+		t.Fatalf("CheckHypertable() before migrations error = %v, want %v", err, ErrNotHypertable)
+	}
+	// Nor can anything be inserted into it, which is what Insert reports
+	// for a table dropped while the collector runs.
+	s := Store{DB: PoolQuerier{Pool: pool}}
+	payload := metrics.Payload{Service: "Entry-Hub", Timestamp: time.Now().UTC().Format(time.RFC3339)}
+	if err := s.Insert(ctx, payload); !errors.Is(err, ErrTableMissing) {
+		t.Fatalf("Insert() before migrations error = %v, want %v", err, ErrTableMissing)
+	}
+
+	migrationsDir, err := filepath.Abs("../../migrations")
+	if err != nil {
+		t.Fatalf("resolve migrations directory: %v", err)
+	}
+	m, err := schema.New(databaseURL, migrationsDir)
+	if err != nil {
+		t.Fatalf("schema.New: %v", err)
+	}
+	if err := schema.Apply(m); err != nil {
+		t.Fatalf("schema.Apply: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			t.Errorf("roll back migrations during cleanup: %v", err)
+		}
+	})
+
+	if err := CheckHypertable(ctx, pool); err != nil {
+		t.Fatalf("CheckHypertable() after migrations error = %v, want nil", err)
+	}
+
+	// The quarantine table is bounded too: a hypertable with its own
+	// retention job, so untrusted publishers cannot grow it forever.
+	var retentionJobs int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM timescaledb_information.jobs
+		WHERE proc_name = 'policy_retention' AND hypertable_name = 'rejected_metrics'`).Scan(&retentionJobs); err != nil {
+		t.Fatalf("query rejected_metrics retention job: %v", err)
+	}
+	if retentionJobs != 1 {
+		t.Fatalf("rejected_metrics retention jobs = %d, want 1", retentionJobs)
+	}
+
+	// 000005's down migration turns it back into 000002's plain table,
+	// keeping its rows, and 000005 and the later migrations then apply
+	// again on top of that.
+	if err := s.InsertRejected(ctx, "metrics/Unknown", "unrecognized_topic"); err != nil {
+		t.Fatalf("InsertRejected() error = %v", err)
+	}
+	if err := m.Migrate(4); err != nil {
+		t.Fatalf("roll back to 000004: %v", err)
+	}
+	var hypertables, rejected int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM timescaledb_information.hypertables
+		WHERE hypertable_name = 'rejected_metrics'`).Scan(&hypertables); err != nil {
+		t.Fatalf("query rejected_metrics hypertable: %v", err)
+	}
+	if hypertables != 0 {
+		t.Fatal("rejected_metrics is still a hypertable after rolling back 000005")
+	}
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM rejected_metrics`).Scan(&rejected); err != nil {
+		t.Fatalf("count rejected_metrics rows: %v", err)
+	}
+	if rejected != 1 {
+		t.Fatalf("rejected_metrics rows after rolling back 000005 = %d, want 1", rejected)
+	}
+	if err := schema.Apply(m); err != nil {
+		t.Fatalf("re-apply 000005 onwards: %v", err)
+	}
+}
+
+// Requirement: MT-F-03
+func TestReader_Postgres(t *testing.T) {
+	databaseURL := os.Getenv(databaseURLEnvVar)
+	if databaseURL == "" {
+		t.Skipf("%s not set; skipping the real-TimescaleDB MT-F-03 test (see TestStore_Insert_Postgres).", databaseURLEnvVar)
+	}
+
+	ctx := context.Background()
+
+	migrationsDir, err := filepath.Abs("../../migrations")
+	if err != nil {
+		t.Fatalf("resolve migrations directory: %v", err)
+	}
+	m, err := schema.New(databaseURL, migrationsDir)
+	if err != nil {
+		t.Fatalf("schema.New: %v", err)
+	}
+	if err := schema.Apply(m); err != nil {
+		t.Fatalf("schema.Apply: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			t.Errorf("roll back migrations during cleanup: %v", err)
+		}
+	})
+
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	s := Store{DB: PoolQuerier{Pool: pool}}
+	newest := time.Now().UTC().Truncate(time.Second)
+	for _, ts := range []time.Time{newest.Add(-time.Minute), newest} {
+		payload := metrics.Payload{Service: "Entry-Hub", Timestamp: ts.Format(time.RFC3339), RequestCount: 10, ErrorCount: 3}
+		if err := s.Insert(ctx, payload); err != nil {
+			t.Fatalf("Insert(): %v", err)
+		}
+	}
+	if err := s.InsertRejected(ctx, "metrics/Unknown", "unrecognized_topic"); err != nil {
+		t.Fatalf("InsertRejected(): %v", err)
+	}
+
+	r := Reader{DB: pool}
+
+	lastSeen, err := r.LastSeen(ctx)
+	if err != nil {
+		t.Fatalf("LastSeen(): %v", err)
+	}
+	if len(lastSeen) != 1 || !lastSeen["Entry-Hub"].Equal(newest) {
+		t.Fatalf("LastSeen() = %v, want only Entry-Hub at %v", lastSeen, newest)
+	}
+
+	stored, rejected, err := r.Counts(ctx, newest.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Counts(): %v", err)
+	}
+	if stored != 2 || rejected != 1 {
+		t.Fatalf("Counts() = (%d, %d), want (2, 1)", stored, rejected)
+	}
+
+	requests, errs, err := r.ErrorCounts(ctx, newest.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ErrorCounts(): %v", err)
+	}
+	// Both rows report the same running totals: nothing happened between them.
+	if len(requests) != 1 || requests["Entry-Hub"] != 0 || errs["Entry-Hub"] != 0 {
+		t.Fatalf("ErrorCounts() = (%v, %v), want Entry-Hub at (0, 0)", requests, errs)
+	}
+}
+
+// Requirement: MT-F-03
+func TestReader_ErrorCounts_Postgres(t *testing.T) {
+	databaseURL := os.Getenv(databaseURLEnvVar)
+	if databaseURL == "" {
+		t.Skipf("%s not set; skipping the real-TimescaleDB MT-F-03 test (see TestStore_Insert_Postgres).", databaseURLEnvVar)
+	}
+
+	ctx := context.Background()
+
+	migrationsDir, err := filepath.Abs("../../migrations")
+	if err != nil {
+		t.Fatalf("resolve migrations directory: %v", err)
+	}
+	m, err := schema.New(databaseURL, migrationsDir)
+	if err != nil {
+		t.Fatalf("schema.New: %v", err)
+	}
+	if err := schema.Apply(m); err != nil {
+		t.Fatalf("schema.Apply: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			t.Errorf("roll back migrations during cleanup: %v", err)
+		}
+	})
+
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	// Running totals, one row a minute, as every publisher sends them.
+	now := time.Now().UTC().Truncate(time.Second)
+	since := now.Add(-15 * time.Minute)
+	rows := []struct {
+		service          string
+		age              time.Duration
+		requests, errors int64
+	}{
+		// Old traffic, before the window: a big error spike that must
+		// not count, and a large request total that must not dilute.
+		{"Entry-Hub", time.Hour, 100000, 5000},
+		// In the window: 100 requests, 40 of them errors.
+		{"Entry-Hub", 10 * time.Minute, 100000, 5000},
+		{"Entry-Hub", 5 * time.Minute, 100060, 5020},
+		{"Entry-Hub", time.Minute, 100100, 5040},
+		// A restart in the window: 30 requests and 1 error before it,
+		// 8 requests and 2 errors after it.
+		{"Security-Switch", 12 * time.Minute, 500, 10},
+		{"Security-Switch", 8 * time.Minute, 530, 11},
+		{"Security-Switch", 4 * time.Minute, 5, 0},
+		{"Security-Switch", 2 * time.Minute, 8, 2},
+		// A single row in the window has no baseline to grow from.
+		{"Database-Vault", 3 * time.Minute, 700, 70},
+	}
+	s := Store{DB: PoolQuerier{Pool: pool}}
+	for _, row := range rows {
+		payload := metrics.Payload{
+			Service:      row.service,
+			Timestamp:    now.Add(-row.age).Format(time.RFC3339),
+			RequestCount: row.requests,
+			ErrorCount:   row.errors,
+		}
+		if err := s.Insert(ctx, payload); err != nil {
+			t.Fatalf("Insert(): %v", err)
+		}
+	}
+
+	requests, errs, err := Reader{DB: pool}.ErrorCounts(ctx, since)
+	if err != nil {
+		t.Fatalf("ErrorCounts(): %v", err)
+	}
+	want := map[string][2]int64{
+		"Entry-Hub":       {100, 40},
+		"Security-Switch": {38, 3},
+	}
+	if len(requests) != len(want) || len(errs) != len(want) {
+		t.Fatalf("ErrorCounts() = (%v, %v), want only %v", requests, errs, want)
+	}
+	for service, counts := range want {
+		if got := [2]int64{requests[service], errs[service]}; got != counts {
+			t.Errorf("ErrorCounts()[%s] = %v, want %v", service, got, counts)
+		}
+	}
+}
+
+// Requirement: MT-F-03
+func TestReader_CountByService_Postgres(t *testing.T) {
+	databaseURL := os.Getenv(databaseURLEnvVar)
+	if databaseURL == "" {
+		t.Skipf("%s not set; skipping the real-TimescaleDB MT-F-03 test (see TestStore_Insert_Postgres).", databaseURLEnvVar)
+	}
+
+	ctx := context.Background()
+
+	migrationsDir, err := filepath.Abs("../../migrations")
+	if err != nil {
+		t.Fatalf("resolve migrations directory: %v", err)
+	}
+	m, err := schema.New(databaseURL, migrationsDir)
+	if err != nil {
+		t.Fatalf("schema.New: %v", err)
+	}
+	if err := schema.Apply(m); err != nil {
+		t.Fatalf("schema.Apply: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			t.Errorf("roll back migrations during cleanup: %v", err)
+		}
+	})
+
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	s := Store{DB: PoolQuerier{Pool: pool}}
+	end := time.Now().UTC().Truncate(time.Second)
+	start := end.Add(-time.Hour)
+	seed := []struct {
+		service string
+		at      time.Time
+	}{
+		{"Entry-Hub", start},                         // first instant of the window: counted
+		{"Entry-Hub", end.Add(-time.Minute)},         // counted
+		{"Entry-Hub", end},                           // window end is exclusive: not counted
+		{"Database-Vault", start.Add(time.Minute)},   // counted
+		{"Security-Switch", start.Add(-time.Second)}, // before the window: not counted
+	}
+	for _, row := range seed {
+		if err := s.Insert(ctx, metrics.Payload{Service: row.service, Timestamp: row.at.Format(time.RFC3339)}); err != nil {
+			t.Fatalf("Insert(): %v", err)
+		}
+	}
+
+	counts, err := Reader{DB: pool}.CountByService(ctx, start, end)
+	if err != nil {
+		t.Fatalf("CountByService(): %v", err)
+	}
+	want := map[string]int64{"Entry-Hub": 2, "Database-Vault": 1}
+	if len(counts) != len(want) {
+		t.Fatalf("CountByService() = %v, want %v", counts, want)
+	}
+	for service, n := range want {
+		if counts[service] != n {
+			t.Fatalf("CountByService() = %v, want %v", counts, want)
+		}
+	}
+}
+
+// Requirement: MT-F-03
+func TestReader_Latest_Postgres(t *testing.T) {
+	databaseURL := os.Getenv(databaseURLEnvVar)
+	if databaseURL == "" {
+		t.Skipf("%s not set; skipping the real-TimescaleDB MT-F-03 test (see TestStore_Insert_Postgres).", databaseURLEnvVar)
+	}
+
+	ctx := context.Background()
+
+	migrationsDir, err := filepath.Abs("../../migrations")
+	if err != nil {
+		t.Fatalf("resolve migrations directory: %v", err)
+	}
+	m, err := schema.New(databaseURL, migrationsDir)
+	if err != nil {
+		t.Fatalf("schema.New: %v", err)
+	}
+	if err := schema.Apply(m); err != nil {
+		t.Fatalf("schema.Apply: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			t.Errorf("roll back migrations during cleanup: %v", err)
+		}
+	})
+
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	s := Store{DB: PoolQuerier{Pool: pool}}
+	newest := time.Now().UTC().Truncate(time.Second)
+	want := metrics.Payload{
+		Service:                 "Entry-Hub",
+		Timestamp:               newest.Format(time.RFC3339),
+		RequestCount:            30,
+		ErrorCount:              3,
+		AverageResponseTimeMs:   12.5,
+		ActiveConnections:       4,
+		DryRunCount:             7,
+		DryRunErrorCount:        2,
+		MXLookupCount:           6,
+		MXRejectionCount:        1,
+		MXLookupFailureCount:    1,
+		DBPoolAcquired:          3,
+		DBPoolIdle:              7,
+		DBPoolMax:               10,
+		DBPoolWaitDurationMs:    40,
+		HashesInFlight:          1,
+		AverageEncryptionTimeMs: 0.03,
+		DBPoolLeakSuspected:     true,
+	}
+	// Inserted out of time order, so Latest cannot be relying on insertion
+	// order.
+	seed := []metrics.Payload{
+		{Service: "Entry-Hub", Timestamp: newest.Add(-2 * time.Minute).Format(time.RFC3339), RequestCount: 10},
+		want,
+		{Service: "Entry-Hub", Timestamp: newest.Add(-time.Minute).Format(time.RFC3339), RequestCount: 20},
+		{Service: "Database-Vault", Timestamp: newest.Add(time.Minute).Format(time.RFC3339), RequestCount: 99},
+	}
+	for _, payload := range seed {
+		if err := s.Insert(ctx, payload); err != nil {
+			t.Fatalf("Insert(): %v", err)
+		}
+	}
+
+	reader := Reader{DB: pool}
+	got, err := reader.Latest(ctx, "Entry-Hub")
+	if err != nil {
+		t.Fatalf("Latest(): %v", err)
+	}
+	if got != want {
+		t.Fatalf("Latest() = %+v, want %+v", got, want)
+	}
+
+	if _, err := reader.Latest(ctx, "Storage-Service"); !errors.Is(err, ErrNoMetrics) {
+		t.Fatalf("Latest() for a service with no rows error = %v, want wrapping ErrNoMetrics", err)
+	}
+}
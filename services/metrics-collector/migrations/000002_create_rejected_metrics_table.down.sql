@@ -0,0 +1,3 @@
+-- Reverses 000002_create_rejected_metrics_table.up.sql. Test-cleanup-only,
+-- same as 000001's down migration. Dropping the table drops its index.
+DROP TABLE IF EXISTS rejected_metrics;
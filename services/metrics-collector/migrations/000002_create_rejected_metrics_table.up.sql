@@ -0,0 +1,21 @@
+-- Quarantine table for metrics messages internal/collector.Handler.Handle
+-- discards (MT-F-02), written only when RAM_USB_METRICS_COLLECTOR_
+-- REJECTION_MODE is "quarantine" (see cmd/metrics-collector/main.go). Its
+-- purpose is diagnosing a misbehaving publisher after the fact, not
+-- replaying its data, so it deliberately holds no payload content at all:
+-- only when the message arrived, the (already sanitized) topic it arrived
+-- on, and which of the collector's fixed discard reasons applied. A
+-- rejected payload is by definition one this process could not trust, so
+-- storing any of its fields - even the ones that happened to parse -
+-- would be storing unvalidated, publisher-controlled data.
+--
+-- Created as a plain table; 000005_rejected_metrics_retention.up.sql
+-- later turns it into a hypertable with a 30-day retention policy, since
+-- nothing else ever deletes from it.
+CREATE TABLE rejected_metrics (
+    rejected_at  TIMESTAMPTZ NOT NULL,
+    topic        TEXT NOT NULL,
+    reason       TEXT NOT NULL
+);
+
+CREATE INDEX rejected_metrics_rejected_at_idx ON rejected_metrics (rejected_at DESC);
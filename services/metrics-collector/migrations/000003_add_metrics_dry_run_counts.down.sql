@@ -0,0 +1,4 @@
+-- Reverses 000003_add_metrics_dry_run_counts.up.sql. Test-cleanup-only,
+-- same as 000001's down migration.
+ALTER TABLE metrics DROP COLUMN IF EXISTS dry_run_error_count;
+ALTER TABLE metrics DROP COLUMN IF EXISTS dry_run_count;
@@ -0,0 +1,13 @@
+-- Adds pkg/metrics.Payload's two dry-run counters to "metrics":
+--
+--   Payload.DryRunCount      -> dry_run_count (BIGINT)
+--   Payload.DryRunErrorCount -> dry_run_error_count (BIGINT)
+--
+-- Only Entry-Hub's /api/validate produces them; every other service
+-- publishes 0. DEFAULT 0 gives every row stored before this migration the
+-- value it would have carried, and TimescaleDB adds a column with a
+-- constant default to a hypertable with columnstore enabled without
+-- rewriting its compressed chunks - one column per statement, since it
+-- does not accept several ADD COLUMN actions in one ALTER TABLE there.
+ALTER TABLE metrics ADD COLUMN dry_run_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE metrics ADD COLUMN dry_run_error_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Reverses 000004_add_metrics_mx_counts.up.sql. Test-cleanup-only, same
+-- as 000001's down migration.
+ALTER TABLE metrics DROP COLUMN IF EXISTS mx_lookup_failure_count;
+ALTER TABLE metrics DROP COLUMN IF EXISTS mx_rejection_count;
+ALTER TABLE metrics DROP COLUMN IF EXISTS mx_lookup_count;
@@ -0,0 +1,13 @@
+-- Adds pkg/metrics.Payload's three email-domain MX-check counters to
+-- "metrics":
+--
+--   Payload.MXLookupCount        -> mx_lookup_count (BIGINT)
+--   Payload.MXRejectionCount     -> mx_rejection_count (BIGINT)
+--   Payload.MXLookupFailureCount -> mx_lookup_failure_count (BIGINT)
+--
+-- Only Entry-Hub with RAM_USB_ENTRY_HUB_REQUIRE_EMAIL_MX set produces
+-- them. DEFAULT 0 and one column per statement for the same reasons as
+-- 000003_add_metrics_dry_run_counts.up.sql.
+ALTER TABLE metrics ADD COLUMN mx_lookup_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE metrics ADD COLUMN mx_rejection_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE metrics ADD COLUMN mx_lookup_failure_count BIGINT NOT NULL DEFAULT 0;
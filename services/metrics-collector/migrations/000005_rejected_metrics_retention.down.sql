@@ -0,0 +1,13 @@
+-- Reverses 000005_rejected_metrics_retention.up.sql. Test-cleanup-only,
+-- same as 000001's down migration. TimescaleDB cannot turn a hypertable
+-- back into a plain table in place, so its rows are copied into a new
+-- plain table that takes over the name and 000002's index, leaving the
+-- schema exactly as 000002 created it.
+SELECT remove_retention_policy('rejected_metrics', if_exists => true);
+
+CREATE TABLE rejected_metrics_plain (LIKE rejected_metrics INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+INSERT INTO rejected_metrics_plain SELECT * FROM rejected_metrics;
+DROP TABLE rejected_metrics;
+ALTER TABLE rejected_metrics_plain RENAME TO rejected_metrics;
+
+CREATE INDEX rejected_metrics_rejected_at_idx ON rejected_metrics (rejected_at DESC);
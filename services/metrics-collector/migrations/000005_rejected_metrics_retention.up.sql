@@ -0,0 +1,15 @@
+-- Bounds "rejected_metrics" the way MT-F-03 bounds "metrics": the table
+-- is fed by messages the collector could not trust, so a misbehaving or
+-- hostile publisher could otherwise grow it without limit. It becomes a
+-- hypertable partitioned on rejected_at, and a background job drops any
+-- chunk entirely older than 30 days, the same window as "metrics".
+--
+-- migrate_data moves rows already stored into chunks.
+-- create_default_indexes is off because 000002 already created the
+-- (rejected_at DESC) index TimescaleDB would otherwise add a duplicate
+-- of. No columnstore policy: rejected rows are few and short-lived, so
+-- compressing them would save nothing worth a second background job.
+SELECT create_hypertable('rejected_metrics', by_range('rejected_at'),
+    migrate_data => true, create_default_indexes => false);
+
+SELECT add_retention_policy('rejected_metrics', drop_after => INTERVAL '30 days');
@@ -0,0 +1,6 @@
+-- Reverses 000006_add_metrics_db_pool_gauges.up.sql. Test-cleanup-only,
+-- same as 000001's down migration.
+ALTER TABLE metrics DROP COLUMN IF EXISTS db_pool_wait_duration_ms;
+ALTER TABLE metrics DROP COLUMN IF EXISTS db_pool_max;
+ALTER TABLE metrics DROP COLUMN IF EXISTS db_pool_idle;
+ALTER TABLE metrics DROP COLUMN IF EXISTS db_pool_acquired;
@@ -0,0 +1,15 @@
+-- Adds pkg/metrics.Payload's four database connection pool gauges to
+-- "metrics":
+--
+--   Payload.DBPoolAcquired       -> db_pool_acquired (BIGINT)
+--   Payload.DBPoolIdle           -> db_pool_idle (BIGINT)
+--   Payload.DBPoolMax            -> db_pool_max (BIGINT)
+--   Payload.DBPoolWaitDurationMs -> db_pool_wait_duration_ms (BIGINT)
+--
+-- Only Database-Vault's published rows and Metrics-Collector's own rows
+-- carry them. DEFAULT 0 and one column per statement for the same
+-- reasons as 000003_add_metrics_dry_run_counts.up.sql.
+ALTER TABLE metrics ADD COLUMN db_pool_acquired BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE metrics ADD COLUMN db_pool_idle BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE metrics ADD COLUMN db_pool_max BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE metrics ADD COLUMN db_pool_wait_duration_ms BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Reverses 000007_add_metrics_hashes_in_flight.up.sql. Test-cleanup-only,
+-- same as 000001's down migration.
+ALTER TABLE metrics DROP COLUMN IF EXISTS hashes_in_flight;
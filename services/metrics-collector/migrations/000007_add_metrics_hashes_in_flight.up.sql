@@ -0,0 +1,7 @@
+-- Adds pkg/metrics.Payload's password-hashing gauge to "metrics":
+--
+--   Payload.HashesInFlight -> hashes_in_flight (BIGINT)
+--
+-- Only Database-Vault produces it. DEFAULT 0 for the same reasons as
+-- 000003_add_metrics_dry_run_counts.up.sql.
+ALTER TABLE metrics ADD COLUMN hashes_in_flight BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Reverses 000008_add_metrics_encryption_time.up.sql. Test-cleanup-only,
+-- same as 000001's down migration.
+ALTER TABLE metrics DROP COLUMN IF EXISTS average_encryption_time_ms;
@@ -0,0 +1,7 @@
+-- Adds pkg/metrics.Payload's encryption timing to "metrics":
+--
+--   Payload.AverageEncryptionTimeMs -> average_encryption_time_ms (DOUBLE PRECISION)
+--
+-- Only Database-Vault produces it. DEFAULT 0 for the same reasons as
+-- 000003_add_metrics_dry_run_counts.up.sql.
+ALTER TABLE metrics ADD COLUMN average_encryption_time_ms DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Reverses 000009_add_metrics_db_pool_leak_suspected.up.sql.
+-- Test-cleanup-only, same as 000001's down migration.
+ALTER TABLE metrics DROP COLUMN IF EXISTS db_pool_leak_suspected;
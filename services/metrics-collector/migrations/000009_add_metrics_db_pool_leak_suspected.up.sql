@@ -0,0 +1,8 @@
+-- Adds pkg/metrics.Payload's connection leak flag to "metrics":
+--
+--   Payload.DBPoolLeakSuspected -> db_pool_leak_suspected (BOOLEAN)
+--
+-- Only a service running pgpool.MonitorStats ever sets it. DEFAULT false
+-- for the same reasons as 000003_add_metrics_dry_run_counts.up.sql's
+-- DEFAULT 0.
+ALTER TABLE metrics ADD COLUMN db_pool_leak_suspected BOOLEAN NOT NULL DEFAULT false;
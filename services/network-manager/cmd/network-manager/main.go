@@ -73,10 +73,12 @@ import (
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
 	"google.golang.org/grpc"
 
+	"github.com/Verryx-02/RAM-USB/pkg/httpmethod"
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/pkg/metrics"
 	"github.com/Verryx-02/RAM-USB/pkg/mtls"
 	"github.com/Verryx-02/RAM-USB/pkg/pki"
+	"github.com/Verryx-02/RAM-USB/pkg/secrets"
 	"github.com/Verryx-02/RAM-USB/services/network-manager/internal/grants"
 	"github.com/Verryx-02/RAM-USB/services/network-manager/internal/headscale"
 	"github.com/Verryx-02/RAM-USB/services/network-manager/internal/httpapi"
@@ -100,7 +102,8 @@ const (
 	// envHeadscaleAPIKey is the bearer API key internal/headscale.Dial
 	// authenticates with (a Headscale-issued credential, minted
 	// out-of-band by a CA/ops process - "headscale apikeys create" -
-	// distinct from any RAM-USB mTLS certificate).
+	// distinct from any RAM-USB mTLS certificate). May instead be supplied
+	// as a file (see requireSecretEnv).
 	envHeadscaleAPIKey = "RAM_USB_HEADSCALE_API_KEY" //nolint:gosec // an env var *name*, not a credential value
 
 	// envHeadscaleInsecureSkipVerify, if set to "true", skips verifying
@@ -160,6 +163,8 @@ const connectTimeout = 10 * time.Second
 const sweepInterval = 5 * time.Minute
 
 func main() {
+	slog.SetDefault(logging.NewLogger(os.Stderr))
+
 	if err := run(); err != nil {
 		slog.Error("network-manager: fatal startup error", "error", logging.Sanitize(err.Error()))
 		os.Exit(1)
@@ -237,8 +242,8 @@ func run() error {
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc(httpapi.MeshUserPath, handler.CreateMeshUser)
-	mux.HandleFunc(httpapi.GrantPath, handler.Grant)
+	mux.HandleFunc(httpapi.MeshUserPath, httpmethod.Allow(handler.CreateMeshUser, http.MethodPost))
+	mux.HandleFunc(httpapi.GrantPath, httpmethod.Allow(handler.Grant, http.MethodPost))
 
 	httpServer := &http.Server{
 		Addr: listenAddr,
@@ -263,9 +268,13 @@ func run() error {
 	}
 	if metricsClient != nil {
 		defer metricsClient.Disconnect(250)
-		go metrics.Run(ctx, metricsPublishInterval, func(publishCtx context.Context) error {
+		publishAlert, err := metrics.LoadFailureAlert()
+		if err != nil {
+			return fmt.Errorf("build metrics publish alert: %w", err)
+		}
+		go metrics.Run(ctx, metricsPublishInterval, publishAlert.Wrap(func(publishCtx context.Context) error {
 			return metrics.PublishOnce(publishCtx, metricsClient, serviceName, counters.Snapshot())
-		})
+		}))
 	}
 
 	serveErr := make(chan error, 1)
@@ -318,6 +327,21 @@ func requireEnv(name string) (string, error) {
 	return value, nil
 }
 
+// requireSecretEnv is requireEnv for a value carrying a credential: name
+// may instead be supplied as a file whose path is in name+"_FILE"
+// (pkg/secrets), which takes precedence, so the credential need not sit
+// in the process environment at all.
+func requireSecretEnv(name string) (string, error) {
+	value, ok, err := secrets.Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("required environment variable %s (or %s%s) is not set", name, name, secrets.FileSuffix)
+	}
+	return value, nil
+}
+
 // getEnvBool reads name from the environment as a bool, defaulting to
 // false if unset or empty. A value present but not parseable as a bool
 // (strconv.ParseBool's accepted forms: 1/t/T/TRUE/true/True,
@@ -373,7 +397,7 @@ func buildHeadscaleConn() (*grpc.ClientConn, error) {
 	if err != nil {
 		return nil, err
 	}
-	apiKey, err := requireEnv(envHeadscaleAPIKey)
+	apiKey, err := requireSecretEnv(envHeadscaleAPIKey)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +76,7 @@ import (
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
+	"github.com/Verryx-02/RAM-USB/pkg/httpmethod"
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/pkg/metrics"
 	"github.com/Verryx-02/RAM-USB/pkg/mtls"
@@ -146,6 +147,8 @@ const metricsPublishInterval = time.Minute
 const connectTimeout = 10 * time.Second
 
 func main() {
+	slog.SetDefault(logging.NewLogger(os.Stderr))
+
 	if err := run(); err != nil {
 		slog.Error("security-switch: fatal startup error", "error", logging.Sanitize(err.Error()))
 		os.Exit(1)
@@ -190,8 +193,8 @@ func run() error {
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc(httpapi.RegisterPath, handler.Register)
-	mux.HandleFunc(httpapi.LoginPath, handler.Login)
+	mux.HandleFunc(httpapi.RegisterPath, httpmethod.Allow(handler.Register, http.MethodPost))
+	mux.HandleFunc(httpapi.LoginPath, httpmethod.Allow(handler.Login, http.MethodPost))
 
 	httpServer := &http.Server{
 		Addr: listenAddr,
@@ -209,9 +212,13 @@ func run() error {
 	}
 	if metricsClient != nil {
 		defer metricsClient.Disconnect(250)
-		go metrics.Run(ctx, metricsPublishInterval, func(publishCtx context.Context) error {
+		publishAlert, err := metrics.LoadFailureAlert()
+		if err != nil {
+			return fmt.Errorf("build metrics publish alert: %w", err)
+		}
+		go metrics.Run(ctx, metricsPublishInterval, publishAlert.Wrap(func(publishCtx context.Context) error {
 			return metrics.PublishOnce(publishCtx, metricsClient, serviceName, counters.Snapshot())
-		})
+		}))
 	}
 
 	serveErr := make(chan error, 1)
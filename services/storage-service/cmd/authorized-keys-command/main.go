@@ -48,6 +48,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -89,6 +90,8 @@ const organizationDatabaseVault = "DatabaseVault"
 var posixUsernamePattern = regexp.MustCompile(`^user[0-9a-z]{6}$`)
 
 func main() {
+	slog.SetDefault(logging.NewLogger(os.Stderr))
+
 	if len(os.Args) < 2 {
 		slog.Warn("authorized-keys-command: missing username argument")
 		os.Exit(0)
@@ -166,8 +169,9 @@ type config struct {
 	// handshake.
 	clientCertPath string
 	clientKeyPath  string
-	// clientCAPath locates the CA certificate bundle (PEM) trusted to have
-	// issued Database-Vault's server certificate.
+	// clientCAPath locates the CA certificate bundle (PEM), or a
+	// directory of bundles, trusted to have issued Database-Vault's server
+	// certificate (see loadCAPool).
 	clientCAPath string
 }
 
@@ -230,6 +234,52 @@ func parseConfig(r io.Reader) (config, error) {
 	return cfg, nil
 }
 
+// loadCAPool builds the trust pool for Database-Vault's server certificate
+// from path, which is either a single PEM bundle or a directory of them.
+// A bundle may hold several CA certificates, and every .pem/.crt file in a
+// directory is loaded, so during a CA rotation the old and new roots can
+// both be trusted at once: drop the new root in before any certificate it
+// signed is deployed, remove the old one after the last certificate it
+// signed has expired. Any file that holds no certificate is an error
+// rather than silently ignored, since it is most likely a misplaced key or
+// a truncated copy.
+func loadCAPool(path string) (*x509.CertPool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read CA bundle %s: %w", path, err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("read CA directory %s: %w", path, err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.Type().IsRegular() && (ext == ".pem" || ext == ".crt") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no .pem or .crt files found in CA directory %s", path)
+		}
+	}
+
+	pool := x509.NewCertPool()
+	for _, file := range files {
+		caData, err := os.ReadFile(file) //nolint:gosec // path comes from this process's own operator-controlled config file, not from request input
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle %s: %w", file, err)
+		}
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", file)
+		}
+	}
+	return pool, nil
+}
+
 // buildClient assembles the *http.Client this binary uses to call
 // Database-Vault over mTLS, verifying organization="DatabaseVault" on
 // Database-Vault's server certificate. Mirrors the identical
@@ -243,14 +293,9 @@ func buildClient(cfg config) (*http.Client, error) {
 		return nil, fmt.Errorf("load client certificate/key: %w", err)
 	}
 
-	caData, err := os.ReadFile(cfg.clientCAPath) //nolint:gosec // path comes from this process's own operator-controlled config file, not from request input
+	rootCAs, err := loadCAPool(cfg.clientCAPath)
 	if err != nil {
-		return nil, fmt.Errorf("read CA bundle %s: %w", cfg.clientCAPath, err)
-	}
-
-	rootCAs := x509.NewCertPool()
-	if !rootCAs.AppendCertsFromPEM(caData) {
-		return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.clientCAPath)
+		return nil, err
 	}
 
 	return &http.Client{
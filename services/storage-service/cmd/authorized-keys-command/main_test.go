@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/Verryx-02/RAM-USB/pkg/mtls"
 )
 
 // Requirement: ST-F-11
@@ -169,3 +175,78 @@ func TestParseConfig(t *testing.T) {
 		})
 	}
 }
+
+// Requirement: ST-F-11
+func TestLoadCAPool_TrustsEveryCAInBundleOrDirectory(t *testing.T) {
+	oldCA, err := mtls.NewTestCA()
+	if err != nil {
+		t.Fatalf("NewTestCA() error = %v", err)
+	}
+	newCA, err := mtls.NewTestCA()
+	if err != nil {
+		t.Fatalf("NewTestCA() error = %v", err)
+	}
+	otherCA, err := mtls.NewTestCA()
+	if err != nil {
+		t.Fatalf("NewTestCA() error = %v", err)
+	}
+
+	bundleDir := t.TempDir()
+	bundle := filepath.Join(bundleDir, "ca.crt")
+	if err := os.WriteFile(bundle, bytes.Join([][]byte{oldCA.CertPEM(), newCA.CertPEM()}, nil), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	caDir := t.TempDir()
+	for name, pemData := range map[string][]byte{"old.pem": oldCA.CertPEM(), "new.crt": newCA.CertPEM(), "README": []byte("not a certificate")} {
+		if err := os.WriteFile(filepath.Join(caDir, name), pemData, 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	for _, path := range []string{bundle, caDir} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			pool, err := loadCAPool(path)
+			if err != nil {
+				t.Fatalf("loadCAPool() error = %v", err)
+			}
+			for name, ca := range map[string]*mtls.TestCA{"old": oldCA, "new": newCA, "other": otherCA} {
+				leaf, err := ca.IssueLeaf("DatabaseVault", "database-vault")
+				if err != nil {
+					t.Fatalf("IssueLeaf() error = %v", err)
+				}
+				_, verifyErr := leaf.Leaf.Verify(x509.VerifyOptions{Roots: pool})
+				if wantOK := name != "other"; (verifyErr == nil) != wantOK {
+					t.Fatalf("%s CA leaf: Verify() error = %v, want success %v", name, verifyErr, wantOK)
+				}
+			}
+		})
+	}
+}
+
+// Requirement: ST-F-11
+func TestLoadCAPool_RejectsFilesWithoutCertificates(t *testing.T) {
+	dir := t.TempDir()
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "missing path", path: filepath.Join(dir, "missing.crt")},
+		{name: "bundle with no certificate", path: garbage},
+		{name: "directory with no certificate files", path: t.TempDir()},
+		{name: "directory with a non-certificate .pem", path: dir},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadCAPool(tt.path); err == nil {
+				t.Fatalf("loadCAPool(%s) error = nil, want non-nil", tt.path)
+			}
+		})
+	}
+}
@@ -68,6 +68,7 @@ import (
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
+	"github.com/Verryx-02/RAM-USB/pkg/httpmethod"
 	"github.com/Verryx-02/RAM-USB/pkg/logging"
 	"github.com/Verryx-02/RAM-USB/pkg/metrics"
 	"github.com/Verryx-02/RAM-USB/pkg/mtls"
@@ -116,6 +117,8 @@ const metricsPublishInterval = time.Minute
 const connectTimeout = 10 * time.Second
 
 func main() {
+	slog.SetDefault(logging.NewLogger(os.Stderr))
+
 	if err := run(); err != nil {
 		slog.Error("storage-service: fatal startup error", "error", logging.Sanitize(err.Error()))
 		os.Exit(1)
@@ -149,7 +152,7 @@ func run() error {
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc(httpapi.CreateUserPath, handler.CreateUser)
+	mux.HandleFunc(httpapi.CreateUserPath, httpmethod.Allow(handler.CreateUser, http.MethodPost))
 
 	httpServer := &http.Server{
 		Addr: listenAddr,
@@ -167,9 +170,13 @@ func run() error {
 	}
 	if metricsClient != nil {
 		defer metricsClient.Disconnect(250)
-		go metrics.Run(ctx, metricsPublishInterval, func(publishCtx context.Context) error {
+		publishAlert, err := metrics.LoadFailureAlert()
+		if err != nil {
+			return fmt.Errorf("build metrics publish alert: %w", err)
+		}
+		go metrics.Run(ctx, metricsPublishInterval, publishAlert.Wrap(func(publishCtx context.Context) error {
 			return metrics.PublishOnce(publishCtx, metricsClient, serviceName, counters.Snapshot())
-		})
+		}))
 	}
 
 	serveErr := make(chan error, 1)